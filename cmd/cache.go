@@ -0,0 +1,78 @@
+/*
+Copyright © 2020 Romber Li <romber2001@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/romberli/go-util/constant"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/romberli/go-mod/config"
+	"github.com/romberli/go-mod/module/mod"
+	"github.com/romberli/go-mod/pkg/message"
+
+	msgMod "github.com/romberli/go-mod/pkg/message/mod"
+)
+
+// cacheCmd represents the cache command
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "cache command",
+	Long:  `manage the graph cache.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := cmd.Help()
+		if err != nil {
+			fmt.Println(fmt.Sprintf(constant.LogWithStackString, message.NewMessage(message.ErrPrintHelpInfo, err)))
+			os.Exit(constant.DefaultAbnormalExitCode)
+		}
+
+		os.Exit(constant.DefaultNormalExitCode)
+	},
+}
+
+// cacheCleanCmd represents the cache clean command
+var cacheCleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "cache clean command",
+	Long:  `remove all the graph cache files.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// init config
+		err := initConfig()
+		if err != nil {
+			fmt.Println(fmt.Sprintf(constant.LogWithStackString, message.NewMessage(message.ErrInitConfig, err)))
+			os.Exit(constant.DefaultAbnormalExitCode)
+		}
+
+		cacheDir := viper.GetString(config.CacheDirKey)
+		err = mod.CleanGraphCache(cacheDir)
+		if err != nil {
+			fmt.Println(fmt.Sprintf(constant.LogWithStackString, message.NewMessage(msgMod.ErrModCacheClean, err, cacheDir)))
+			os.Exit(constant.DefaultAbnormalExitCode)
+		}
+
+		fmt.Println(message.NewMessage(msgMod.InfoModCacheClean, cacheDir).Error())
+		os.Exit(constant.DefaultNormalExitCode)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheCleanCmd)
+}
@@ -0,0 +1,74 @@
+/*
+Copyright © 2020 Romber Li <romber2001@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/romberli/go-util/constant"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/romberli/go-mod/config"
+	"github.com/romberli/go-mod/pkg/message"
+
+	msgMod "github.com/romberli/go-mod/pkg/message/mod"
+)
+
+// childCmd represents the child command
+var childCmd = &cobra.Command{
+	Use:   config.DefaultModChild,
+	Short: "child command",
+	Long:  `print the dependency tree of the package.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// init config
+		err := initConfig()
+		if err != nil {
+			fmt.Println(fmt.Sprintf(constant.LogWithStackString, message.NewMessage(message.ErrInitConfig, err)))
+			os.Exit(constant.DefaultAbnormalExitCode)
+		}
+
+		modDir := viper.GetString(config.ModDirKey)
+		modName := viper.GetString(config.ModNameKey)
+		modVersion := viper.GetString(config.ModVersionKey)
+		modUseCompileVersion := viper.GetBool(config.ModUseCompileVersionKey)
+
+		c := newModController()
+		err = c.PrintChildTree(modName, modVersion, modUseCompileVersion)
+		if err != nil {
+			fmt.Println(fmt.Sprintf(constant.LogWithStackString, message.NewMessage(
+				msgMod.ErrModChildPrintChildTree, err, modDir, modName, modVersion)))
+		}
+
+		os.Exit(constant.DefaultNormalExitCode)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(childCmd)
+
+	// Here you will define your flags and configuration settings.
+
+	// Cobra supports Persistent Flags which will work for this command
+	// and all subcommands, e.g.:
+	// childCmd.PersistentFlags().String("foo", "", "A help for foo")
+
+	// Cobra supports local flags which will only run when this command
+	// is called directly, e.g.:
+	// childCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+}
@@ -0,0 +1,61 @@
+/*
+Copyright © 2020 Romber Li <romber2001@gmail.com>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/romberli/go-util/constant"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/romberli/go-mod/config"
+	"github.com/romberli/go-mod/pkg/message"
+
+	msgMod "github.com/romberli/go-mod/pkg/message/mod"
+)
+
+// cyclesCmd represents the cycles command
+var cyclesCmd = &cobra.Command{
+	Use:   "cycles",
+	Short: "cycles command",
+	Long:  `print the strongly connected components of the module graph and the edges which close the cycles.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		// init config
+		err := initConfig()
+		if err != nil {
+			fmt.Println(fmt.Sprintf(constant.LogWithStackString, message.NewMessage(message.ErrInitConfig, err)))
+			os.Exit(constant.DefaultAbnormalExitCode)
+		}
+
+		modDir := viper.GetString(config.ModDirKey)
+
+		c := newModController()
+		err = c.PrintCycles()
+		if err != nil {
+			fmt.Println(fmt.Sprintf(constant.LogWithStackString, message.NewMessage(
+				msgMod.ErrModCyclesPrintCycles, err, modDir)))
+		}
+
+		os.Exit(constant.DefaultNormalExitCode)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cyclesCmd)
+}
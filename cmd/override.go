@@ -1,9 +1,12 @@
 package cmd
 
 import (
+	"regexp"
 	"strings"
 
+	"github.com/pingcap/errors"
 	"github.com/romberli/go-util/constant"
+	"github.com/spf13/cast"
 	"github.com/spf13/viper"
 
 	"github.com/romberli/go-mod/config"
@@ -21,6 +24,11 @@ func OverrideConfigByCLI() error {
 	if err != nil {
 		return err
 	}
+	// override cache
+	err = overrideCacheByCLI()
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -51,6 +59,14 @@ func overrideModByCLI() error {
 	if modName != constant.DefaultRandomString {
 		viper.Set(config.ModNameKey, modName)
 	}
+	// mod.nameRegex
+	if modNameRegex != constant.DefaultRandomString {
+		_, err := regexp.Compile(modNameRegex)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		viper.Set(config.ModNameRegexKey, modNameRegex)
+	}
 	// mod.version
 	if modVersion != constant.DefaultRandomString {
 		viper.Set(config.ModVersionKey, modVersion)
@@ -59,6 +75,84 @@ func overrideModByCLI() error {
 	if modUseCompileVersionStr != constant.DefaultRandomString {
 		viper.Set(config.ModUseCompileVersionKey, modUseCompileVersionStr)
 	}
+	// mod.resolver
+	if modResolver != constant.DefaultRandomString {
+		viper.Set(config.ModResolverKey, strings.ToLower(modResolver))
+	}
+	// mod.workers
+	if modWorkersStr != constant.DefaultRandomString {
+		viper.Set(config.ModWorkersKey, modWorkersStr)
+	}
+	// mod.workFile
+	if modWorkFile != constant.DefaultRandomString {
+		viper.Set(config.ModWorkFileKey, modWorkFile)
+	}
+	// mod.selectedOnly
+	if modSelectedOnlyStr != constant.DefaultRandomString {
+		viper.Set(config.ModSelectedOnlyKey, modSelectedOnlyStr)
+	}
+	// mod.fetch
+	if modFetchStr != constant.DefaultRandomString {
+		viper.Set(config.ModFetchKey, modFetchStr)
+	}
+	// mod.indirect
+	if modIndirectStr != constant.DefaultRandomString {
+		viper.Set(config.ModIndirectKey, modIndirectStr)
+	}
+	// mod.prune
+	if modPruneStr != constant.DefaultRandomString {
+		viper.Set(config.ModPruneKey, modPruneStr)
+	}
+	// mod.maxChains
+	if modMaxChainsStr != constant.DefaultRandomString {
+		viper.Set(config.ModMaxChainsKey, modMaxChainsStr)
+	}
+	// mod.maxDepth
+	if modMaxDepthStr != constant.DefaultRandomString {
+		viper.Set(config.ModMaxDepthKey, modMaxDepthStr)
+	}
+	// mod.shortest
+	if modShortestStr != constant.DefaultRandomString {
+		viper.Set(config.ModShortestKey, modShortestStr)
+	}
+	// mod.kShortest
+	if modKShortestStr != constant.DefaultRandomString {
+		viper.Set(config.ModKShortestKey, modKShortestStr)
+	}
+	// mod.pathPrefix
+	if modPathPrefix != constant.DefaultRandomString {
+		viper.Set(config.ModPathPrefixKey, modPathPrefix)
+	}
+	// mod.parentFormat
+	if modParentFormat != constant.DefaultRandomString {
+		viper.Set(config.ModParentFormatKey, strings.ToLower(modParentFormat))
+	}
+	// mod.outputFormat
+	if modOutputFormat != constant.DefaultRandomString {
+		viper.Set(config.ModOutputFormatKey, strings.ToLower(modOutputFormat))
+	}
+	// mod.failOnMultipleMajors
+	if modFailOnMultiMajorsStr != constant.DefaultRandomString {
+		viper.Set(config.ModFailOnMultiMajorsKey, modFailOnMultiMajorsStr)
+	}
+
+	return nil
+}
+
+// overrideCacheByCLI overrides the cache section by command line interface
+func overrideCacheByCLI() error {
+	// cache.enabled
+	if noCacheStr != constant.DefaultRandomString {
+		noCache, err := cast.ToBoolE(noCacheStr)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		viper.Set(config.CacheEnabledKey, !noCache)
+	}
+	// cache.dir
+	if cacheDir != constant.DefaultRandomString {
+		viper.Set(config.CacheDirKey, cacheDir)
+	}
 
 	return nil
 }
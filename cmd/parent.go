@@ -25,7 +25,6 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/romberli/go-mod/config"
-	"github.com/romberli/go-mod/module/mod"
 	"github.com/romberli/go-mod/pkg/message"
 
 	msgMod "github.com/romberli/go-mod/pkg/message/mod"
@@ -33,7 +32,7 @@ import (
 
 // parentCmd represents the parent command
 var parentCmd = &cobra.Command{
-	Use:   "parent",
+	Use:   config.DefaultModParent,
 	Short: "parent command",
 	Long:  `print parent of the package.`,
 	Run: func(cmd *cobra.Command, args []string) {
@@ -49,7 +48,7 @@ var parentCmd = &cobra.Command{
 		modVersion := viper.GetString(config.ModVersionKey)
 		modUseCompileVersion := viper.GetBool(config.ModUseCompileVersionKey)
 
-		c := mod.NewController(modDir)
+		c := newModController()
 		err = c.PrintParentChain(modName, modVersion, modUseCompileVersion)
 		if err != nil {
 			fmt.Println(fmt.Sprintf(constant.LogWithStackString, message.NewMessage(
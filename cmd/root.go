@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 
 	"github.com/pingcap/errors"
 	"github.com/romberli/go-util/constant"
@@ -28,6 +29,7 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/romberli/go-mod/config"
+	"github.com/romberli/go-mod/module/mod"
 	"github.com/romberli/go-mod/pkg/message"
 )
 
@@ -43,8 +45,27 @@ var (
 	// mod
 	modDir                  string
 	modName                 string
+	modNameRegex            string
 	modVersion              string
 	modUseCompileVersionStr string
+	modResolver             string
+	modWorkersStr           string
+	modWorkFile             string
+	modSelectedOnlyStr      string
+	modFetchStr             string
+	modIndirectStr          string
+	modPruneStr             string
+	modMaxChainsStr         string
+	modMaxDepthStr          string
+	modParentFormat         string
+	modShortestStr          string
+	modKShortestStr         string
+	modPathPrefix           string
+	modOutputFormat         string
+	modFailOnMultiMajorsStr string
+	// cache
+	noCacheStr string
+	cacheDir   string
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -96,9 +117,31 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", constant.DefaultRandomString, fmt.Sprintf("specify the log format(default: %s)", log.DefaultLogFormat))
 	// mod
 	rootCmd.PersistentFlags().StringVar(&modDir, "mod-dir", constant.DefaultRandomString, fmt.Sprintf("specify the mod directory(default: %s)", config.DefaultModDir))
-	rootCmd.PersistentFlags().StringVar(&modName, "mod-name", constant.DefaultRandomString, fmt.Sprintf("specify the mod name(default: %s)", config.DefaultModName))
+	rootCmd.PersistentFlags().StringVar(&modName, "mod-name", constant.DefaultRandomString, fmt.Sprintf("specify the mod name, it could be a glob pattern which matches the leading elements of the module paths, such as golang.org/x/*(default: %s)", config.DefaultModName))
+	rootCmd.PersistentFlags().StringVar(&modNameRegex, "mod-name-regex", constant.DefaultRandomString, fmt.Sprintf("specify the regular expression of the module paths, it is used if the mod name is not specified(default: %s)", config.DefaultModNameRegex))
 	rootCmd.PersistentFlags().StringVar(&modVersion, "mod-version", constant.DefaultRandomString, fmt.Sprintf("specify the mod version(default: %s)", config.DefaultModVersion))
 	rootCmd.PersistentFlags().StringVar(&modUseCompileVersionStr, "mod-use-compile-version", constant.DefaultRandomString, fmt.Sprintf("specify if use compile version(default: %t)", config.DefaultModUseCompileVersion))
+	rootCmd.PersistentFlags().StringVar(&modResolver, "mod-resolver", constant.DefaultRandomString, fmt.Sprintf("specify the mod resolver, available: [auto, modfile, list, graph, vendor](default: %s)", config.DefaultModResolver))
+	rootCmd.PersistentFlags().StringVar(&modWorkersStr, "mod-workers", constant.DefaultRandomString, fmt.Sprintf("specify the number of workers which resolve the requirements concurrently(default: %d)", config.DefaultModWorkers))
+	rootCmd.PersistentFlags().StringVar(&modWorkFile, "mod-work-file", constant.DefaultRandomString, fmt.Sprintf("specify the go.work file(default: %s)", config.DefaultModWorkFile))
+	rootCmd.PersistentFlags().StringVar(&modSelectedOnlyStr, "mod-selected-only", constant.DefaultRandomString, fmt.Sprintf("specify if only print the parent chains which lead to the selected version(default: %t)", config.DefaultModSelectedOnly))
+	rootCmd.PersistentFlags().StringVar(&modFetchStr, "mod-fetch", constant.DefaultRandomString, fmt.Sprintf("specify if fetch the go.mod files which are missing in the module cache from the proxies(default: %t)", config.DefaultModFetch))
+	rootCmd.PersistentFlags().StringVar(&modIndirectStr, "mod-indirect", constant.DefaultRandomString, fmt.Sprintf("specify if include the indirect requirements(default: %t)", config.DefaultModIndirect))
+	rootCmd.PersistentFlags().StringVar(&modPruneStr, "mod-prune", constant.DefaultRandomString, fmt.Sprintf("specify if apply the module graph pruning of go 1.17 and later(default: %t)", config.DefaultModPrune))
+	rootCmd.PersistentFlags().StringVar(&modMaxChainsStr, "max-chains", constant.DefaultRandomString, fmt.Sprintf("specify the max number of the parent chains which are printed, 0 means no limit(default: %d)", config.DefaultModMaxChains))
+	rootCmd.PersistentFlags().StringVar(&modMaxDepthStr, "max-depth", constant.DefaultRandomString, fmt.Sprintf("specify the max number of the edges of a parent chain or the max depth of the dependency tree, 0 means no limit(default: %d)", config.DefaultModMaxDepth))
+	rootCmd.PersistentFlags().StringVar(&modParentFormat, "parent-format", constant.DefaultRandomString, fmt.Sprintf("specify how the parent chains are printed, chain, count or dag(default: %s)", config.DefaultModParentFormat))
+	rootCmd.PersistentFlags().StringVar(&modShortestStr, "shortest", constant.DefaultRandomString, fmt.Sprintf("specify if only print the shortest parent chain(default: %t)", config.DefaultModShortest))
+	rootCmd.PersistentFlags().Lookup("shortest").NoOptDefVal = constant.TrueString
+	rootCmd.PersistentFlags().StringVar(&modKShortestStr, "k", constant.DefaultRandomString, fmt.Sprintf("specify the number of the shortest parent chains which are printed, 0 means all(default: %d)", config.DefaultModKShortest))
+	rootCmd.PersistentFlags().StringVar(&modPathPrefix, "path-prefix", constant.DefaultRandomString, fmt.Sprintf("specify the comma-separated module path prefixes of the dependency trees(default: %s)", config.DefaultModPathPrefix))
+	rootCmd.PersistentFlags().StringVar(&modOutputFormat, "output-format", constant.DefaultRandomString, fmt.Sprintf("specify the output format of the versions, conflicts and majors commands, text or json(default: %s)", config.DefaultModOutputFormat))
+	rootCmd.PersistentFlags().StringVar(&modFailOnMultiMajorsStr, "fail-on-multiple-majors", constant.DefaultRandomString, fmt.Sprintf("specify if the majors command fails when any module is selected at more than one major version(default: %t)", config.DefaultModFailOnMultiMajors))
+	rootCmd.PersistentFlags().Lookup("fail-on-multiple-majors").NoOptDefVal = constant.TrueString
+	// cache
+	rootCmd.PersistentFlags().StringVar(&noCacheStr, "no-cache", constant.DefaultRandomString, fmt.Sprintf("specify if disable the graph cache(default: %t)", !config.DefaultCacheEnabled))
+	rootCmd.PersistentFlags().Lookup("no-cache").NoOptDefVal = constant.TrueString
+	rootCmd.PersistentFlags().StringVar(&cacheDir, "cache-dir", constant.DefaultRandomString, fmt.Sprintf("specify the graph cache directory(default: %s)", config.DefaultCacheDir))
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
@@ -143,6 +186,48 @@ func initConfig() error {
 	return nil
 }
 
+// newModController returns a new mod controller with the mod and cache configuration
+func newModController() *mod.Controller {
+	return mod.NewController(
+		viper.GetString(config.ModDirKey),
+		mod.NameRegexOption(getNameRegex()),
+		mod.ResolverOption(viper.GetString(config.ModResolverKey)),
+		mod.WorkersOption(viper.GetInt(config.ModWorkersKey)),
+		mod.WorkFileOption(viper.GetString(config.ModWorkFileKey)),
+		mod.SelectedOnlyOption(viper.GetBool(config.ModSelectedOnlyKey)),
+		mod.FetchOption(viper.GetBool(config.ModFetchKey)),
+		mod.IndirectOption(viper.GetBool(config.ModIndirectKey)),
+		mod.PruneOption(viper.GetBool(config.ModPruneKey)),
+		mod.ChainLimitOption(viper.GetInt(config.ModMaxChainsKey), viper.GetInt(config.ModMaxDepthKey)),
+		mod.ParentFormatOption(viper.GetString(config.ModParentFormatKey)),
+		mod.KShortestOption(getKShortest()),
+		mod.PathPrefixOption(viper.GetString(config.ModPathPrefixKey)),
+		mod.OutputFormatOption(viper.GetString(config.ModOutputFormatKey)),
+		mod.FailOnMultipleMajorsOption(viper.GetBool(config.ModFailOnMultiMajorsKey)),
+		mod.CacheOption(viper.GetBool(config.CacheEnabledKey), viper.GetString(config.CacheDirKey)),
+	)
+}
+
+// getKShortest returns the number of the shortest parent chains which are printed
+func getKShortest() int {
+	k := viper.GetInt(config.ModKShortestKey)
+	if k == constant.ZeroInt && viper.GetBool(config.ModShortestKey) {
+		return constant.OneInt
+	}
+
+	return k
+}
+
+// getNameRegex returns the regular expression of the module paths
+func getNameRegex() *regexp.Regexp {
+	nameRegex := viper.GetString(config.ModNameRegexKey)
+	if nameRegex == constant.EmptyString {
+		return nil
+	}
+
+	return regexp.MustCompile(nameRegex)
+}
+
 // initDefaultConfig initiate default configuration
 func initDefaultConfig() (err error) {
 	// get base dir
@@ -190,8 +275,8 @@ Aliases:
 Examples:
 {{.Example}}{{end}}{{if .HasAvailableSubCommands}}
 
-Available Commands:{{range .Commands}}{{if (or .IsAvailableCommand (eq .FullName "help"))}}
-  {{rpad .FullName .NamePadding }} {{.Short}}{{end}}{{end}}{{end}}{{if .HasAvailableLocalFlags}}
+Available Commands:{{range .Commands}}{{if (or .IsAvailableCommand (eq .Name "help"))}}
+  {{rpad .Name .NamePadding }} {{.Short}}{{end}}{{end}}{{end}}{{if .HasAvailableLocalFlags}}
 
 Flags:
 {{.LocalFlags.FlagUsagesWithoutDefault | trimTrailingWhitespaces}}{{end}}{{if .HasAvailableInheritedFlags}}
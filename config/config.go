@@ -26,8 +26,11 @@ import (
 )
 
 var (
-	ValidLogLevels  = []string{"debug", "info", "warn", "warning", "error", "fatal"}
-	ValidLogFormats = []string{"text", "json"}
+	ValidLogLevels        = []string{"debug", "info", "warn", "warning", "error", "fatal"}
+	ValidLogFormats       = []string{"text", "json"}
+	ValidModResolvers     = []string{ModResolverAuto, ModResolverModFile, ModResolverList, ModResolverGraph, ModResolverVendor}
+	ValidModParentFormats = []string{ModParentFormatChain, ModParentFormatCount, ModParentFormatDAG}
+	ValidModOutputFormats = []string{ModOutputFormatText, ModOutputFormatJSON}
 )
 
 // SetDefaultConfig set default configuration, it is the lowest priority
@@ -36,6 +39,8 @@ func SetDefaultConfig(baseDir string) {
 	SetDefaultLog(baseDir)
 	// mod
 	SetDefaultMod()
+	// cache
+	SetDefaultCache()
 }
 
 // SetDefaultLog sets the default value of log
@@ -48,8 +53,30 @@ func SetDefaultLog(baseDir string) {
 func SetDefaultMod() {
 	viper.SetDefault(ModDirKey, DefaultModDir)
 	viper.SetDefault(ModNameKey, DefaultModName)
+	viper.SetDefault(ModNameRegexKey, DefaultModNameRegex)
 	viper.SetDefault(ModVersionKey, DefaultModVersion)
 	viper.SetDefault(ModUseCompileVersionKey, DefaultModUseCompileVersion)
+	viper.SetDefault(ModResolverKey, DefaultModResolver)
+	viper.SetDefault(ModWorkersKey, DefaultModWorkers)
+	viper.SetDefault(ModWorkFileKey, DefaultModWorkFile)
+	viper.SetDefault(ModSelectedOnlyKey, DefaultModSelectedOnly)
+	viper.SetDefault(ModFetchKey, DefaultModFetch)
+	viper.SetDefault(ModIndirectKey, DefaultModIndirect)
+	viper.SetDefault(ModPruneKey, DefaultModPrune)
+	viper.SetDefault(ModMaxChainsKey, DefaultModMaxChains)
+	viper.SetDefault(ModMaxDepthKey, DefaultModMaxDepth)
+	viper.SetDefault(ModParentFormatKey, DefaultModParentFormat)
+	viper.SetDefault(ModShortestKey, DefaultModShortest)
+	viper.SetDefault(ModKShortestKey, DefaultModKShortest)
+	viper.SetDefault(ModPathPrefixKey, DefaultModPathPrefix)
+	viper.SetDefault(ModOutputFormatKey, DefaultModOutputFormat)
+	viper.SetDefault(ModFailOnMultiMajorsKey, DefaultModFailOnMultiMajors)
+}
+
+// SetDefaultCache sets the default value of cache
+func SetDefaultCache() {
+	viper.SetDefault(CacheEnabledKey, DefaultCacheEnabled)
+	viper.SetDefault(CacheDirKey, DefaultCacheDir)
 }
 
 // TrimSpaceOfArg trims spaces of given argument
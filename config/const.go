@@ -28,10 +28,45 @@ const (
 	DefaultModParent            = "parent"
 	DefaultModChild             = "child"
 	DefaultModAll               = "all"
+	DefaultModVersions          = "versions"
+	DefaultModConflicts         = "conflicts"
+	DefaultModMajors            = "majors"
 	DefaultModDir               = "./"
 	DefaultModName              = constant.EmptyString
+	DefaultModNameRegex         = constant.EmptyString
 	DefaultModVersion           = constant.EmptyString
 	DefaultModUseCompileVersion = false
+	DefaultModResolver          = ModResolverAuto
+	DefaultModWorkers           = 8
+	DefaultModWorkFile          = constant.EmptyString
+	DefaultModSelectedOnly      = false
+	DefaultModFetch             = false
+	DefaultModIndirect          = false
+	DefaultModPrune             = false
+	DefaultModMaxChains         = 0
+	DefaultModMaxDepth          = 0
+	DefaultModParentFormat      = ModParentFormatChain
+	DefaultModShortest          = false
+	DefaultModKShortest         = 0
+	DefaultModPathPrefix        = constant.EmptyString
+	DefaultModOutputFormat      = ModOutputFormatText
+	DefaultModFailOnMultiMajors = false
+
+	DefaultCacheEnabled = true
+	DefaultCacheDir     = constant.EmptyString
+
+	ModResolverModFile = "modfile"
+	ModResolverList    = "list"
+	ModResolverGraph   = "graph"
+	ModResolverVendor  = "vendor"
+	ModResolverAuto    = "auto"
+
+	ModParentFormatChain = "chain"
+	ModParentFormatCount = "count"
+	ModParentFormatDAG   = "dag"
+
+	ModOutputFormatText = "text"
+	ModOutputFormatJSON = "json"
 )
 
 // configuration constant
@@ -41,6 +76,25 @@ const (
 
 	ModDirKey               = "mod.dir"
 	ModNameKey              = "mod.name"
+	ModNameRegexKey         = "mod.nameRegex"
 	ModVersionKey           = "mod.version"
 	ModUseCompileVersionKey = "mod.useCompileVersion"
+	ModResolverKey          = "mod.resolver"
+	ModWorkersKey           = "mod.workers"
+	ModWorkFileKey          = "mod.workFile"
+	ModSelectedOnlyKey      = "mod.selectedOnly"
+	ModFetchKey             = "mod.fetch"
+	ModIndirectKey          = "mod.indirect"
+	ModPruneKey             = "mod.prune"
+	ModMaxChainsKey         = "mod.maxChains"
+	ModMaxDepthKey          = "mod.maxDepth"
+	ModParentFormatKey      = "mod.parentFormat"
+	ModShortestKey          = "mod.shortest"
+	ModKShortestKey         = "mod.kShortest"
+	ModPathPrefixKey        = "mod.pathPrefix"
+	ModOutputFormatKey      = "mod.outputFormat"
+	ModFailOnMultiMajorsKey = "mod.failOnMultipleMajors"
+
+	CacheEnabledKey = "cache.enabled"
+	CacheDirKey     = "cache.dir"
 )
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"regexp"
+
 	"github.com/pingcap/errors"
 	"github.com/romberli/go-multierror"
 	"github.com/romberli/go-util/common"
@@ -25,6 +27,11 @@ func ValidateConfig() (err error) {
 	if err != nil {
 		merr = multierror.Append(merr, err)
 	}
+	// validate cache section
+	err = ValidateCache()
+	if err != nil {
+		merr = multierror.Append(merr, err)
+	}
 
 	return errors.Trace(merr.ErrorOrNil())
 }
@@ -73,6 +80,16 @@ func ValidateMod() error {
 		merr = multierror.Append(merr, errors.Trace(err))
 	}
 
+	// validate mod.nameRegex
+	modNameRegex, err := cast.ToStringE(viper.Get(ModNameRegexKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+	_, err = regexp.Compile(modNameRegex)
+	if err != nil {
+		merr = multierror.Append(merr, message.NewMessage(message.ErrNotValidModNameRegex, modNameRegex, err.Error()))
+	}
+
 	// validate mod.version
 	_, err = cast.ToStringE(viper.Get(ModVersionKey))
 	if err != nil {
@@ -85,5 +102,135 @@ func ValidateMod() error {
 		merr = multierror.Append(merr, errors.Trace(err))
 	}
 
+	// validate mod.resolver
+	modResolver, err := cast.ToStringE(viper.Get(ModResolverKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+	if !common.ElementInSlice(ValidModResolvers, modResolver) {
+		merr = multierror.Append(merr, message.NewMessage(message.ErrNotValidModResolver, modResolver))
+	}
+
+	// validate mod.workers
+	modWorkers, err := cast.ToIntE(viper.Get(ModWorkersKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+	if modWorkers < constant.OneInt {
+		merr = multierror.Append(merr, message.NewMessage(message.ErrNotValidModWorkers, modWorkers))
+	}
+
+	// validate mod.workFile
+	_, err = cast.ToStringE(viper.Get(ModWorkFileKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+
+	// validate mod.selectedOnly
+	_, err = cast.ToBoolE(viper.Get(ModSelectedOnlyKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+
+	// validate mod.fetch
+	_, err = cast.ToBoolE(viper.Get(ModFetchKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+
+	// validate mod.indirect
+	_, err = cast.ToBoolE(viper.Get(ModIndirectKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+
+	// validate mod.prune
+	_, err = cast.ToBoolE(viper.Get(ModPruneKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+
+	// validate mod.maxChains
+	modMaxChains, err := cast.ToIntE(viper.Get(ModMaxChainsKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+	if modMaxChains < constant.ZeroInt {
+		merr = multierror.Append(merr, message.NewMessage(message.ErrNotValidModMaxChains, modMaxChains))
+	}
+
+	// validate mod.maxDepth
+	modMaxDepth, err := cast.ToIntE(viper.Get(ModMaxDepthKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+	if modMaxDepth < constant.ZeroInt {
+		merr = multierror.Append(merr, message.NewMessage(message.ErrNotValidModMaxDepth, modMaxDepth))
+	}
+
+	// validate mod.parentFormat
+	modParentFormat, err := cast.ToStringE(viper.Get(ModParentFormatKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+	if !common.ElementInSlice(ValidModParentFormats, modParentFormat) {
+		merr = multierror.Append(merr, message.NewMessage(message.ErrNotValidModParentFormat, modParentFormat))
+	}
+
+	// validate mod.shortest
+	_, err = cast.ToBoolE(viper.Get(ModShortestKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+
+	// validate mod.kShortest
+	modKShortest, err := cast.ToIntE(viper.Get(ModKShortestKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+	if modKShortest < constant.ZeroInt {
+		merr = multierror.Append(merr, message.NewMessage(message.ErrNotValidModKShortest, modKShortest))
+	}
+
+	// validate mod.pathPrefix
+	_, err = cast.ToStringE(viper.Get(ModPathPrefixKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+
+	// validate mod.outputFormat
+	modOutputFormat, err := cast.ToStringE(viper.Get(ModOutputFormatKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+	if !common.ElementInSlice(ValidModOutputFormats, modOutputFormat) {
+		merr = multierror.Append(merr, message.NewMessage(message.ErrNotValidModOutputFormat, modOutputFormat))
+	}
+
+	// validate mod.failOnMultipleMajors
+	_, err = cast.ToBoolE(viper.Get(ModFailOnMultiMajorsKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+
+	return merr.ErrorOrNil()
+}
+
+// ValidateCache validates if cache section is valid.
+func ValidateCache() error {
+	merr := &multierror.Error{}
+
+	// validate cache.enabled
+	_, err := cast.ToBoolE(viper.Get(CacheEnabledKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+
+	// validate cache.dir
+	_, err = cast.ToStringE(viper.Get(CacheDirKey))
+	if err != nil {
+		merr = multierror.Append(merr, errors.Trace(err))
+	}
+
 	return merr.ErrorOrNil()
 }
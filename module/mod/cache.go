@@ -0,0 +1,252 @@
+package mod
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/go-util/constant"
+	"github.com/romberli/log"
+)
+
+const (
+	// graphCacheFormatVersion should be increased whenever the cache file or the graph building changes
+	graphCacheFormatVersion = "8"
+	graphCacheDirName       = "go-mod"
+	graphCacheFileExt       = ".json"
+	goSumFileName           = "go.sum"
+	goWorkSumFileExt        = ".sum"
+	getGoVersionCommand     = "go env GOVERSION"
+)
+
+// cachedNode is the serialized node
+type cachedNode struct {
+	Node
+	ParentNodes []int `json:"ParentNodes"`
+	ChildNodes  []int `json:"ChildNodes"`
+}
+
+// cachedGraph is the serialized graph
+type cachedGraph struct {
+	RootNodes             []int         `json:"RootNodes"`
+	Nodes                 []*cachedNode `json:"Nodes"`
+	VendorInconsistencies []string      `json:"VendorInconsistencies"`
+}
+
+// getGraphCacheDir returns the directory of the graph cache
+func getGraphCacheDir(cacheDir string) (string, error) {
+	if cacheDir != constant.EmptyString {
+		return filepath.Abs(cacheDir)
+	}
+
+	userCacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return constant.EmptyString, errors.Trace(err)
+	}
+
+	return filepath.Join(userCacheDir, graphCacheDirName), nil
+}
+
+// CleanGraphCache removes all the cache files in the graph cache directory
+func CleanGraphCache(cacheDir string) error {
+	dir, err := getGraphCacheDir(cacheDir)
+	if err != nil {
+		return err
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, constant.AsteriskString+graphCacheFileExt))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, file := range files {
+		err = os.Remove(file)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	return nil
+}
+
+// getGraphCacheKey returns the key of the graph cache, it is the hash of everything which affects the graph
+func (c *Controller) getGraphCacheKey(directives *Directives) (string, error) {
+	goVersion, err := c.executor.ExecuteCommand(constant.EmptyString, getGoVersionCommand, false)
+	if err != nil {
+		return constant.EmptyString, errors.Annotatef(err, "output: %s", goVersion)
+	}
+
+	h := sha256.New()
+	for _, part := range []string{graphCacheFormatVersion, c.resolverType, strings.TrimSpace(goVersion), defaultPackageRootPath, c.baseDir, strconv.FormatBool(c.proxy != nil), strconv.FormatBool(c.indirect), strconv.FormatBool(c.prune)} {
+		h.Write([]byte(part + constant.CRLFString))
+	}
+
+	var files []string
+	workFile, err := c.getWorkFile()
+	if err != nil {
+		return constant.EmptyString, err
+	}
+	if workFile != constant.EmptyString {
+		files = append(files, workFile, workFile+goWorkSumFileExt)
+	}
+	for _, rootNode := range c.RootNodes {
+		files = append(files,
+			filepath.Join(rootNode.RootPath, goModFileName),
+			filepath.Join(rootNode.RootPath, goSumFileName),
+			filepath.Join(rootNode.RootPath, vendorDirName, vendorModulesFileName),
+		)
+	}
+	// the nested modules are resolved with their own directives
+	allDirectives := []*Directives{directives}
+	for _, rootNode := range c.getNestedRootNodes() {
+		f, err := parseModFile(filepath.Join(rootNode.RootPath, goModFileName), true)
+		if err != nil {
+			return constant.EmptyString, err
+		}
+		allDirectives = append(allDirectives, NewDirectives(rootNode.RootPath, f))
+	}
+	var replaceDirs []string
+	for _, d := range allDirectives {
+		for _, r := range d.replaces {
+			if r.dir != constant.EmptyString {
+				replaceDirs = append(replaceDirs, filepath.Join(r.dir, goModFileName))
+			}
+		}
+	}
+	sort.Strings(replaceDirs)
+	files = append(files, replaceDirs...)
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil && !os.IsNotExist(err) {
+			return constant.EmptyString, errors.Trace(err)
+		}
+		h.Write([]byte(file + constant.CRLFString))
+		h.Write(data)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// getGraphCacheFile returns the path of the cache file of given key
+func (c *Controller) getGraphCacheFile(key string) (string, error) {
+	dir, err := getGraphCacheDir(c.cacheDir)
+	if err != nil {
+		return constant.EmptyString, err
+	}
+
+	return filepath.Join(dir, key+graphCacheFileExt), nil
+}
+
+// loadGraphCache loads the graph from the cache file of given key, it returns false if the cache does not exist
+func (c *Controller) loadGraphCache(key string) (bool, error) {
+	file, err := c.getGraphCacheFile(key)
+	if err != nil {
+		return false, err
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, errors.Trace(err)
+	}
+
+	cg := &cachedGraph{}
+	err = json.Unmarshal(data, cg)
+	if err != nil {
+		log.Warnf("graph cache file is broken, will ignore it. file: %s, error: %s", file, err.Error())
+		return false, nil
+	}
+
+	nodes := make([]*Node, len(cg.Nodes))
+	for i, cn := range cg.Nodes {
+		node := cn.Node
+		node.ParentNodes = nil
+		node.ChildNodes = nil
+		nodes[i] = &node
+	}
+	for i, cn := range cg.Nodes {
+		for _, index := range cn.ParentNodes {
+			nodes[i].AddParentNode(nodes[index])
+		}
+		for _, index := range cn.ChildNodes {
+			nodes[i].AddChildNode(nodes[index])
+		}
+		if nodes[i].FullName != constant.EmptyString {
+			c.m[nodes[i].FullName] = nodes[i]
+		}
+	}
+
+	c.RootNodes = nil
+	for _, index := range cg.RootNodes {
+		c.RootNodes = append(c.RootNodes, nodes[index])
+	}
+	if len(c.RootNodes) > constant.ZeroInt {
+		c.RootNode = c.RootNodes[constant.ZeroInt]
+	}
+	c.vendorInconsistencies = cg.VendorInconsistencies
+
+	return true, nil
+}
+
+// saveGraphCache saves the graph to the cache file of given key
+func (c *Controller) saveGraphCache(key string) error {
+	indexes := make(map[*Node]int)
+	cg := &cachedGraph{VendorInconsistencies: c.vendorInconsistencies}
+	for _, node := range append(append([]*Node{}, c.RootNodes...), c.GetAllNodes()...) {
+		_, ok := indexes[node]
+		if ok {
+			continue
+		}
+		indexes[node] = len(cg.Nodes)
+		cg.Nodes = append(cg.Nodes, &cachedNode{Node: *node})
+	}
+	for _, cn := range cg.Nodes {
+		for _, parentNode := range cn.Node.ParentNodes {
+			cn.ParentNodes = append(cn.ParentNodes, indexes[parentNode])
+		}
+		for _, childNode := range cn.Node.ChildNodes {
+			cn.ChildNodes = append(cn.ChildNodes, indexes[childNode])
+		}
+	}
+	for _, rootNode := range c.RootNodes {
+		cg.RootNodes = append(cg.RootNodes, indexes[rootNode])
+	}
+
+	data, err := json.Marshal(cg)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	file, err := c.getGraphCacheFile(key)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(file), constant.DefaultExecFileMode)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	// write to a temporary file first, so that the other processes never read a partial cache file
+	tmpFile, err := os.CreateTemp(filepath.Dir(file), filepath.Base(file)+constant.AsteriskString)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() { _ = os.Remove(tmpFile.Name()) }()
+	_, err = tmpFile.Write(data)
+	if err != nil {
+		_ = tmpFile.Close()
+		return errors.Trace(err)
+	}
+	err = tmpFile.Close()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	return errors.Trace(os.Rename(tmpFile.Name(), file))
+}
@@ -0,0 +1,218 @@
+package mod
+
+import (
+	"math/big"
+
+	"github.com/romberli/go-util/constant"
+)
+
+// ChainCount is the number of the parent chains which go through the top node and its child node
+type ChainCount struct {
+	Top   *Node
+	Child *Node
+	Count *big.Int
+}
+
+// AncestorGraph is the subgraph of the node and its ancestors
+type AncestorGraph struct {
+	Target *Node
+	// Nodes are sorted topologically, the target node is the last one
+	Nodes []*Node
+	// Tops are the nodes which do not have any parent
+	Tops []*Node
+
+	members map[*Node]bool
+	// counts are the numbers of the paths from the nodes down to the target node
+	counts map[*Node]*big.Int
+}
+
+// NewAncestorGraph returns the ancestor subgraph of the node
+func NewAncestorGraph(target *Node) *AncestorGraph {
+	ag := &AncestorGraph{
+		Target:  target,
+		members: make(map[*Node]bool),
+		counts:  make(map[*Node]*big.Int),
+	}
+
+	// find all the ancestors, and then keep the ones which could be reached from a top node
+	ancestors := map[*Node]bool{target: true}
+	queue := []*Node{target}
+	var tops []*Node
+	for len(queue) > constant.ZeroInt {
+		node := queue[constant.ZeroInt]
+		queue = queue[constant.OneInt:]
+		if len(node.ParentNodes) == constant.ZeroInt {
+			tops = append(tops, node)
+		}
+		for _, parentNode := range ag.getParentNodes(node) {
+			if !ancestors[parentNode] {
+				ancestors[parentNode] = true
+				queue = append(queue, parentNode)
+			}
+		}
+	}
+	queue = tops
+	for _, top := range tops {
+		ag.members[top] = true
+	}
+	for len(queue) > constant.ZeroInt {
+		node := queue[constant.ZeroInt]
+		queue = queue[constant.OneInt:]
+		if node.Pruned {
+			continue
+		}
+		for _, childNode := range node.ChildNodes {
+			if ancestors[childNode] && !ag.members[childNode] {
+				ag.members[childNode] = true
+				queue = append(queue, childNode)
+			}
+		}
+	}
+	if !ag.members[target] {
+		return ag
+	}
+
+	// sort the nodes topologically with the depth first search from the target node
+	done := make(map[*Node]bool)
+	onPath := make(map[*Node]bool)
+	var sortNode func(node *Node)
+	sortNode = func(node *Node) {
+		done[node] = true
+		onPath[node] = true
+		for _, parentNode := range ag.GetParentNodes(node) {
+			if !done[parentNode] && !onPath[parentNode] {
+				sortNode(parentNode)
+			}
+		}
+		delete(onPath, node)
+		ag.Nodes = append(ag.Nodes, node)
+		if len(node.ParentNodes) == constant.ZeroInt {
+			ag.Tops = append(ag.Tops, node)
+		}
+	}
+	sortNode(target)
+
+	return ag
+}
+
+// getParentNodes returns the parent nodes of the node which requirements are not pruned
+func (ag *AncestorGraph) getParentNodes(node *Node) []*Node {
+	var result []*Node
+	for _, parentNode := range node.ParentNodes {
+		if !parentNode.Pruned {
+			result = append(result, parentNode)
+		}
+	}
+
+	return result
+}
+
+// GetParentNodes returns the parent nodes of the node in the ancestor subgraph
+func (ag *AncestorGraph) GetParentNodes(node *Node) []*Node {
+	var result []*Node
+	for _, parentNode := range ag.getParentNodes(node) {
+		if ag.members[parentNode] {
+			result = append(result, parentNode)
+		}
+	}
+
+	return result
+}
+
+// GetChildNodes returns the child nodes of the node in the ancestor subgraph
+func (ag *AncestorGraph) GetChildNodes(node *Node) []*Node {
+	if node.Pruned {
+		return nil
+	}
+
+	var result []*Node
+	for _, childNode := range node.ChildNodes {
+		if ag.members[childNode] {
+			result = append(result, childNode)
+		}
+	}
+
+	return result
+}
+
+// Count returns the number of the simple paths from the node down to the target node
+func (ag *AncestorGraph) Count(node *Node) *big.Int {
+	if len(ag.counts) == constant.ZeroInt && len(ag.Nodes) > constant.ZeroInt {
+		ag.countPaths()
+	}
+
+	count, ok := ag.counts[node]
+	if !ok {
+		return big.NewInt(constant.ZeroInt)
+	}
+
+	return count
+}
+
+// countPaths computes the numbers of the simple paths from all the nodes to the target node
+func (ag *AncestorGraph) countPaths() {
+	order := make(map[*Node]int, len(ag.Nodes))
+	for i, node := range ag.Nodes {
+		order[node] = i
+	}
+	for i, node := range ag.Nodes {
+		for _, childNode := range ag.GetChildNodes(node) {
+			if order[childNode] <= i {
+				// the edge closes a cycle
+				for _, n := range ag.Nodes {
+					ag.counts[n] = ag.countSimplePaths(n, make(map[*Node]bool))
+				}
+				return
+			}
+		}
+	}
+
+	for i := len(ag.Nodes) - constant.OneInt; i >= constant.ZeroInt; i-- {
+		node := ag.Nodes[i]
+		if node == ag.Target {
+			ag.counts[node] = big.NewInt(constant.OneInt)
+			continue
+		}
+		count := big.NewInt(constant.ZeroInt)
+		for _, childNode := range ag.GetChildNodes(node) {
+			count.Add(count, ag.counts[childNode])
+		}
+		ag.counts[node] = count
+	}
+}
+
+// countSimplePaths returns the number of the simple paths from the node to the target node
+func (ag *AncestorGraph) countSimplePaths(node *Node, onPath map[*Node]bool) *big.Int {
+	if node == ag.Target {
+		return big.NewInt(constant.OneInt)
+	}
+
+	count := big.NewInt(constant.ZeroInt)
+	onPath[node] = true
+	for _, childNode := range ag.GetChildNodes(node) {
+		if !onPath[childNode] {
+			count.Add(count, ag.countSimplePaths(childNode, onPath))
+		}
+	}
+	delete(onPath, node)
+
+	return count
+}
+
+// CountParentChains returns the total number of the parent chains and the numbers per direct dependency
+func (ag *AncestorGraph) CountParentChains() (*big.Int, []*ChainCount) {
+	total := big.NewInt(constant.ZeroInt)
+	var counts []*ChainCount
+
+	for _, top := range ag.Tops {
+		total.Add(total, ag.Count(top))
+		if top == ag.Target {
+			continue
+		}
+		for _, childNode := range ag.GetChildNodes(top) {
+			counts = append(counts, &ChainCount{Top: top, Child: childNode, Count: ag.Count(childNode)})
+		}
+	}
+
+	return total, counts
+}
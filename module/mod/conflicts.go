@@ -0,0 +1,164 @@
+package mod
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/go-util/constant"
+
+	"github.com/romberli/go-mod/config"
+)
+
+const (
+	noConflictMessage        = "no module is required at more than one version"
+	conflictHeaderTemplate   = "%s: %s .. %s (%s, selected: %s)\n"
+	conflictChainTemplate    = "%s: %s"
+	lowestString             = "lowest"
+	highestString            = "highest"
+	noRequirerChainString    = "no requirer chain"
+	unknownSelectedString    = "unknown"
+	defaultConflictChainsNum = 1
+)
+
+// semverLevelRanks are the ranks of the semver levels
+var semverLevelRanks = map[string]int{
+	semverLevelNone:       0,
+	semverLevelPrerelease: 1,
+	semverLevelPatch:      2,
+	semverLevelMinor:      3,
+	semverLevelMajor:      4,
+}
+
+// Conflict is a module path which is required at more than one version
+type Conflict struct {
+	Module       string          `json:"module"`
+	Selected     string          `json:"selected"`
+	Lowest       string          `json:"lowest"`
+	Highest      string          `json:"highest"`
+	Versions     int             `json:"versions"`
+	Spread       *SemverDistance `json:"spread"`
+	LowestChain  []string        `json:"lowestChain"`
+	HighestChain []string        `json:"highestChain"`
+}
+
+// GetConflicts returns the module paths which are required at more than one version ranked by the semver spread
+func (c *Controller) GetConflicts() []*Conflict {
+	versions := make(map[string][]*Node)
+	for _, node := range c.getMainNodes() {
+		if node.IsRoot() {
+			continue
+		}
+		versions[node.Name] = append(versions[node.Name], node)
+	}
+
+	var result []*Conflict
+	for name, nodes := range versions {
+		if len(nodes) <= constant.OneInt {
+			continue
+		}
+		// the nodes are already sorted by the semantic version
+		lowest, highest := nodes[constant.ZeroInt], nodes[len(nodes)-constant.OneInt]
+		conflict := &Conflict{
+			Module:       name,
+			Lowest:       lowest.Version,
+			Highest:      highest.Version,
+			Versions:     len(nodes),
+			Spread:       NewSemverDistance(highest.Version, lowest.Version),
+			LowestChain:  getRequirerChain(lowest),
+			HighestChain: getRequirerChain(highest),
+		}
+		for _, node := range nodes {
+			if node.Selected {
+				conflict.Selected = node.Version
+			}
+		}
+		result = append(result, conflict)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i].Spread, result[j].Spread
+		if semverLevelRanks[a.Level] != semverLevelRanks[b.Level] {
+			return semverLevelRanks[a.Level] > semverLevelRanks[b.Level]
+		}
+		if a.Delta != b.Delta {
+			return a.Delta > b.Delta
+		}
+
+		return result[i].Module < result[j].Module
+	})
+
+	return result
+}
+
+// getRequirerChain returns the names of the nodes of the shortest parent chain of the node
+func getRequirerChain(node *Node) []string {
+	result := []string{}
+
+	chains := GetShortestParentChains([]*Node{node}, defaultConflictChainsNum, constant.ZeroInt)
+	if len(chains) == constant.ZeroInt {
+		return result
+	}
+	for _, n := range chains[constant.ZeroInt] {
+		name := n.FullName
+		if name == constant.EmptyString {
+			name = n.RootPath
+		}
+		result = append(result, name)
+	}
+
+	return result
+}
+
+// PrintConflicts prints the module paths which are required at more than one version
+func (c *Controller) PrintConflicts() error {
+	err := c.Init()
+	if err != nil {
+		return err
+	}
+
+	conflicts := c.GetConflicts()
+	if c.outputFormat == config.ModOutputFormatJSON {
+		if conflicts == nil {
+			conflicts = []*Conflict{}
+		}
+		data, err := json.MarshalIndent(conflicts, constant.EmptyString, jsonIndent)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(conflicts) == constant.ZeroInt {
+		fmt.Println(noConflictMessage)
+		return nil
+	}
+
+	for _, conflict := range conflicts {
+		selected := conflict.Selected
+		if selected == constant.EmptyString {
+			selected = unknownSelectedString
+		}
+		spread := strings.TrimSuffix(conflict.Spread.String(), constant.SpaceString+aheadString)
+		if spread == constant.EmptyString {
+			spread = conflict.Spread.Level
+		}
+		fmt.Printf(conflictHeaderTemplate, conflict.Module, conflict.Lowest, conflict.Highest, spread, selected)
+		fmt.Println(outputPrefix + fmt.Sprintf(conflictChainTemplate, lowestString, getChainString(conflict.LowestChain)))
+		fmt.Println(outputPrefixLast + fmt.Sprintf(conflictChainTemplate, highestString, getChainString(conflict.HighestChain)))
+	}
+
+	return nil
+}
+
+// getChainString returns the text representation of the requirer chain
+func getChainString(chain []string) string {
+	if len(chain) == constant.ZeroInt {
+		return noRequirerChainString
+	}
+
+	return strings.Join(chain, edgeArrowString)
+}
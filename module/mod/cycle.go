@@ -0,0 +1,155 @@
+package mod
+
+import (
+	"sort"
+
+	"github.com/romberli/go-util/constant"
+)
+
+// Edge is a requirement from the parent node to the child node
+type Edge struct {
+	Parent   *Node
+	Child    *Node
+	Indirect bool
+	Pruned   bool
+}
+
+// NewEdge returns a new *Edge from the parent node to the child node
+func NewEdge(parent, child *Node) *Edge {
+	return &Edge{Parent: parent, Child: child, Indirect: parent.IsIndirectChild(child), Pruned: parent.Pruned}
+}
+
+// String returns the text representation of the edge
+func (e *Edge) String() string {
+	result := e.Parent.FullName + edgeArrowString + e.Child.FullName
+	if e.Indirect {
+		result += constant.SpaceString + constant.LeftParenthesisString + indirectString + constant.RightParenthesisString
+	}
+	if e.Pruned {
+		result += constant.SpaceString + constant.LeftParenthesisString + prunedString + constant.RightParenthesisString
+	}
+
+	return result
+}
+
+// Cycle is a strongly connected component of the graph which contains cycles
+type Cycle struct {
+	Nodes        []*Node
+	ClosingEdges []*Edge
+}
+
+// FindCycles returns all the strongly connected components which contain cycles
+func FindCycles(nodes []*Node) []*Cycle {
+	t := &tarjan{
+		indexes:  make(map[*Node]int),
+		lowLinks: make(map[*Node]int),
+		onStack:  make(map[*Node]bool),
+	}
+	for _, node := range nodes {
+		if _, ok := t.indexes[node]; !ok {
+			t.strongConnect(node)
+		}
+	}
+
+	var result []*Cycle
+	for _, component := range t.components {
+		inComponent := make(map[*Node]bool)
+		for _, node := range component {
+			inComponent[node] = true
+		}
+		if len(component) == constant.OneInt && !hasChildNode(component[constant.ZeroInt], component[constant.ZeroInt]) {
+			continue
+		}
+		sortNodes(component)
+
+		cycle := &Cycle{Nodes: component}
+		visited := make(map[*Node]bool)
+		onPath := make(map[*Node]bool)
+		for _, node := range component {
+			if !visited[node] {
+				cycle.findClosingEdges(node, inComponent, visited, onPath)
+			}
+		}
+		result = append(result, cycle)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return lessNode(result[i].Nodes[constant.ZeroInt], result[j].Nodes[constant.ZeroInt])
+	})
+
+	return result
+}
+
+// findClosingEdges finds the back edges with depth first search within the component
+func (c *Cycle) findClosingEdges(node *Node, inComponent, visited, onPath map[*Node]bool) {
+	visited[node] = true
+	onPath[node] = true
+
+	for _, childNode := range node.ChildNodes {
+		if !inComponent[childNode] {
+			continue
+		}
+		if onPath[childNode] {
+			c.ClosingEdges = append(c.ClosingEdges, NewEdge(node, childNode))
+			continue
+		}
+		if !visited[childNode] {
+			c.findClosingEdges(childNode, inComponent, visited, onPath)
+		}
+	}
+
+	delete(onPath, node)
+}
+
+// hasChildNode returns if the child node is one of the child nodes of the node
+func hasChildNode(node, childNode *Node) bool {
+	for _, n := range node.ChildNodes {
+		if n == childNode {
+			return true
+		}
+	}
+
+	return false
+}
+
+// tarjan finds the strongly connected components with tarjan's algorithm
+type tarjan struct {
+	index      int
+	indexes    map[*Node]int
+	lowLinks   map[*Node]int
+	onStack    map[*Node]bool
+	stack      []*Node
+	components [][]*Node
+}
+
+func (t *tarjan) strongConnect(node *Node) {
+	t.indexes[node] = t.index
+	t.lowLinks[node] = t.index
+	t.index++
+	t.stack = append(t.stack, node)
+	t.onStack[node] = true
+
+	for _, childNode := range node.ChildNodes {
+		if _, ok := t.indexes[childNode]; !ok {
+			t.strongConnect(childNode)
+			t.lowLinks[node] = min(t.lowLinks[node], t.lowLinks[childNode])
+		} else if t.onStack[childNode] {
+			t.lowLinks[node] = min(t.lowLinks[node], t.indexes[childNode])
+		}
+	}
+
+	if t.lowLinks[node] != t.indexes[node] {
+		return
+	}
+
+	var component []*Node
+	for {
+		last := t.stack[len(t.stack)-constant.OneInt]
+		t.stack = t.stack[:len(t.stack)-constant.OneInt]
+		t.onStack[last] = false
+		component = append(component, last)
+		if last == node {
+			break
+		}
+	}
+	t.components = append(t.components, component)
+}
@@ -0,0 +1,134 @@
+package mod
+
+import (
+	"path/filepath"
+
+	"github.com/romberli/go-util/constant"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// Directives holds the directives of the main modules which affect the whole graph
+type Directives struct {
+	replaces         map[module.Version]*replacement
+	excludes         map[module.Version]bool
+	workspaceModules map[string]bool
+}
+
+// replacement is the target of a replace directive
+type replacement struct {
+	module.Version
+	dir string
+}
+
+// NewDirectives returns a new *Directives with the go.mod file of the main module
+func NewDirectives(baseDir string, f *modfile.File) *Directives {
+	d := &Directives{
+		replaces:         make(map[module.Version]*replacement),
+		excludes:         make(map[module.Version]bool),
+		workspaceModules: make(map[string]bool),
+	}
+	d.AddModFile(baseDir, f)
+
+	return d
+}
+
+// AddModFile adds the replace and exclude directives of the go.mod file of a main module
+func (d *Directives) AddModFile(baseDir string, f *modfile.File) {
+	if f == nil {
+		return
+	}
+
+	d.addReplaces(baseDir, f.Replace)
+	for _, exclude := range f.Exclude {
+		d.excludes[exclude.Mod] = true
+	}
+}
+
+// AddWorkFile adds the replace directives of the go.work file and the workspace modules
+func (d *Directives) AddWorkFile(baseDir string, f *modfile.WorkFile, modulePaths []string) {
+	for _, modulePath := range modulePaths {
+		d.workspaceModules[modulePath] = true
+	}
+	if f == nil {
+		return
+	}
+
+	d.addReplaces(baseDir, f.Replace)
+}
+
+// addReplaces adds the replace directives
+func (d *Directives) addReplaces(baseDir string, replaces []*modfile.Replace) {
+	for _, replace := range replaces {
+		r := &replacement{Version: replace.New}
+		if replace.New.Version == constant.EmptyString {
+			// local path replacement
+			r.dir = replace.New.Path
+			if !filepath.IsAbs(r.dir) {
+				r.dir = filepath.Join(baseDir, r.dir)
+			}
+		}
+		d.replaces[replace.Old] = r
+	}
+}
+
+// Replace applies the replacement of the node if there is any
+func (d *Directives) Replace(node *Node) {
+	if d == nil || node.IsRoot() {
+		return
+	}
+
+	r, ok := d.replaces[module.Version{Path: node.Name, Version: node.Version}]
+	if !ok {
+		r, ok = d.replaces[module.Version{Path: node.Name}]
+		if !ok {
+			return
+		}
+	}
+
+	node.ReplaceName = r.Path
+	node.ReplaceVersion = r.Version.Version
+	node.ReplaceDir = r.dir
+}
+
+// IsExcluded returns if the module version is excluded by the main module
+func (d *Directives) IsExcluded(name, version string) bool {
+	if d == nil {
+		return false
+	}
+
+	return d.excludes[module.Version{Path: name, Version: version}]
+}
+
+// IsWorkspaceModule returns if the module is one of the workspace modules
+func (d *Directives) IsWorkspaceModule(name string) bool {
+	if d == nil {
+		return false
+	}
+
+	return d.workspaceModules[name]
+}
+
+// IsWorkspace returns if the main modules are the workspace modules
+func (d *Directives) IsWorkspace() bool {
+	if d == nil {
+		return false
+	}
+
+	return len(d.workspaceModules) > constant.ZeroInt
+}
+
+// IsReplaceDir returns if the directory is the target of a local path replacement
+func (d *Directives) IsReplaceDir(dir string) bool {
+	if d == nil {
+		return false
+	}
+
+	for _, r := range d.replaces {
+		if r.dir != constant.EmptyString && filepath.Clean(r.dir) == filepath.Clean(dir) {
+			return true
+		}
+	}
+
+	return false
+}
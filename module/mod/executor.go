@@ -0,0 +1,95 @@
+package mod
+
+import (
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/go-util/constant"
+	"github.com/romberli/go-util/linux"
+)
+
+// Executor executes the external commands, such as go env, go list and go mod graph
+type Executor interface {
+	// ExecuteCommand executes the command in given directory and returns the combined output
+	ExecuteCommand(dir, command string, useShell bool) (string, error)
+}
+
+// OSExecutor executes the commands with the operating system
+type OSExecutor struct{}
+
+// NewOSExecutor returns a new *OSExecutor
+func NewOSExecutor() *OSExecutor {
+	return &OSExecutor{}
+}
+
+// ExecuteCommand executes the command in given directory and returns the combined output
+func (oe *OSExecutor) ExecuteCommand(dir, command string, useShell bool) (string, error) {
+	var options []linux.CommandOption
+	if dir != constant.EmptyString {
+		options = append(options, linux.WorkDirOption(dir))
+	}
+	if useShell {
+		options = append(options, linux.UseSHCOption())
+	}
+
+	return linux.ExecuteCommand(command, options...)
+}
+
+// fakeOutput is the scripted output of a command
+type fakeOutput struct {
+	output string
+	err    error
+}
+
+// FakeExecutor returns the scripted outputs instead of executing the commands
+type FakeExecutor struct {
+	mutex    sync.Mutex
+	outputs  map[string]*fakeOutput
+	commands []string
+}
+
+// NewFakeExecutor returns a new *FakeExecutor
+func NewFakeExecutor() *FakeExecutor {
+	return &FakeExecutor{
+		outputs: make(map[string]*fakeOutput),
+	}
+}
+
+// AddOutput scripts the output of the command in given directory, empty dir matches any directory
+func (fe *FakeExecutor) AddOutput(dir, command, output string, err error) {
+	fe.mutex.Lock()
+	defer fe.mutex.Unlock()
+
+	fe.outputs[fe.getKey(dir, command)] = &fakeOutput{output: output, err: err}
+}
+
+// ExecuteCommand returns the scripted output of the command
+func (fe *FakeExecutor) ExecuteCommand(dir, command string, useShell bool) (string, error) {
+	fe.mutex.Lock()
+	defer fe.mutex.Unlock()
+
+	fe.commands = append(fe.commands, command)
+
+	fo, ok := fe.outputs[fe.getKey(dir, command)]
+	if !ok {
+		fo, ok = fe.outputs[fe.getKey(constant.EmptyString, command)]
+		if !ok {
+			return constant.EmptyString, errors.Errorf("FakeExecutor.ExecuteCommand(): command is not scripted. dir: %s, command: %s", dir, command)
+		}
+	}
+
+	return fo.output, fo.err
+}
+
+// GetCommands returns all the executed commands in order
+func (fe *FakeExecutor) GetCommands() []string {
+	fe.mutex.Lock()
+	defer fe.mutex.Unlock()
+
+	return append([]string(nil), fe.commands...)
+}
+
+// getKey returns the key of the scripted output
+func (fe *FakeExecutor) getKey(dir, command string) string {
+	return dir + constant.VerticalBarString + command
+}
@@ -0,0 +1,114 @@
+package mod
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/go-util/constant"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+
+	"github.com/romberli/go-mod/config"
+)
+
+const (
+	noMultipleMajorsMessage = "no module is selected at more than one major version"
+	multipleMajorsTemplate  = "%s: %d major versions\n"
+)
+
+// MajorVersion is a selected major version of the module with its shortest requirer chain
+type MajorVersion struct {
+	Path     string   `json:"path"`
+	Selected string   `json:"selected"`
+	Chain    []string `json:"chain"`
+}
+
+// MultipleMajors is a module which is selected at more than one major version
+type MultipleMajors struct {
+	Module string          `json:"module"`
+	Majors []*MajorVersion `json:"majors"`
+}
+
+// GetMultipleMajors returns the modules which are selected at more than one major version
+func (c *Controller) GetMultipleMajors() []*MultipleMajors {
+	groups := make(map[string][]*Node)
+	for _, node := range c.getMainNodes() {
+		if node.IsRoot() || !node.Selected {
+			continue
+		}
+		prefix, _, ok := module.SplitPathVersion(node.Name)
+		if !ok {
+			prefix = node.Name
+		}
+		groups[prefix] = append(groups[prefix], node)
+	}
+
+	var result []*MultipleMajors
+	for prefix, nodes := range groups {
+		if len(nodes) <= constant.OneInt {
+			continue
+		}
+		sort.SliceStable(nodes, func(i, j int) bool {
+			return semver.Compare(nodes[i].Version, nodes[j].Version) < constant.ZeroInt
+		})
+		mm := &MultipleMajors{Module: prefix}
+		for _, node := range nodes {
+			mm.Majors = append(mm.Majors, &MajorVersion{Path: node.Name, Selected: node.Version, Chain: getRequirerChain(node)})
+		}
+		result = append(result, mm)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Module < result[j].Module
+	})
+
+	return result
+}
+
+// PrintMultipleMajors prints the modules which are selected at more than one major version
+func (c *Controller) PrintMultipleMajors() error {
+	err := c.Init()
+	if err != nil {
+		return err
+	}
+
+	multipleMajors := c.GetMultipleMajors()
+	if c.outputFormat == config.ModOutputFormatJSON {
+		if multipleMajors == nil {
+			multipleMajors = []*MultipleMajors{}
+		}
+		data, err := json.MarshalIndent(multipleMajors, constant.EmptyString, jsonIndent)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		fmt.Println(string(data))
+	} else {
+		if len(multipleMajors) == constant.ZeroInt {
+			fmt.Println(noMultipleMajorsMessage)
+		}
+		for _, mm := range multipleMajors {
+			fmt.Printf(multipleMajorsTemplate, mm.Module, len(mm.Majors))
+			for i, major := range mm.Majors {
+				fmt.Println(getOutputPrefix(i, len(mm.Majors)) + major.Path + AtString + major.Selected)
+				indent := outputIndent
+				if i == len(mm.Majors)-constant.OneInt {
+					indent = outputIndentLast
+				}
+				fmt.Println(indent + outputPrefixLast + getChainString(major.Chain))
+			}
+		}
+	}
+
+	if c.failOnMultipleMajors && len(multipleMajors) > constant.ZeroInt {
+		var modules []string
+		for _, mm := range multipleMajors {
+			modules = append(modules, mm.Module)
+		}
+		return errors.Errorf("Controller.PrintMultipleMajors(): modules are selected at more than one major version. modules: %s",
+			strings.Join(modules, constant.CommaString))
+	}
+
+	return nil
+}
@@ -2,12 +2,16 @@ package mod
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/pingcap/errors"
 	"github.com/romberli/go-util/constant"
-	"github.com/romberli/go-util/linux"
+	"github.com/romberli/log"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
 
 	"github.com/romberli/go-mod/config"
 )
@@ -17,9 +21,23 @@ const (
 	goModGraphCommand         = "go mod graph"
 	goModListCommandTemplate  = "go list -m %s"
 
+	getGoWorkCommand = "go env GOWORK"
+	goWorkOff        = "off"
+
 	defaultSpaceNum  = 2
 	outputPrefix     = "├ "
 	outputPrefixLast = "└ "
+
+	indirectString = "indirect"
+
+	chainCountTemplate   = "%s: %s chains\n"
+	moduleHeaderTemplate = "# %s\n"
+	globMetaChars        = "*?["
+	maxChainsWarning     = "there are more parent chains than the max chains, the others are not printed. max chains: %d"
+
+	noCycleMessage      = "no cycle found"
+	cycleHeaderTemplate = "cycle %d: %d modules\n"
+	closingEdgesHeader  = "closing edges:"
 )
 
 var (
@@ -27,20 +45,172 @@ var (
 )
 
 type Controller struct {
-	baseDir string
+	baseDir      string
+	resolverType string
+	workers      int
+	workFile     string
+	selectedOnly bool
+	executor     Executor
+	cacheEnabled bool
+	cacheDir     string
+	fetch        bool
+	proxy        *ProxyClient
+	indirect     bool
+	prune        bool
+	maxChains    int
+	maxDepth     int
+	parentFormat string
+	kShortest    int
+	pathPrefix   string
+	outputFormat string
+
+	failOnMultipleMajors bool
+	nameRegex            *regexp.Regexp
+
+	vendorInconsistencies []string
+
+	// RootNode is the main module or the first workspace module, RootNodes contains all the main modules
+	RootNode  *Node
+	RootNodes []*Node
+	m         map[string]*Node
+}
+
+// Option is used to customize the controller
+type Option func(c *Controller)
+
+// NameRegexOption specifies the regular expression of the module paths
+func NameRegexOption(nameRegex *regexp.Regexp) Option {
+	return func(c *Controller) {
+		c.nameRegex = nameRegex
+	}
+}
+
+// ResolverOption specifies the resolver type which is used to resolve the requirements of the modules
+func ResolverOption(resolverType string) Option {
+	return func(c *Controller) {
+		c.resolverType = resolverType
+	}
+}
+
+// WorkersOption specifies the number of workers which resolve the requirements of the modules concurrently
+func WorkersOption(workers int) Option {
+	return func(c *Controller) {
+		c.workers = workers
+	}
+}
+
+// WorkFileOption specifies the go.work file
+func WorkFileOption(workFile string) Option {
+	return func(c *Controller) {
+		c.workFile = workFile
+	}
+}
+
+// SelectedOnlyOption specifies if only print the parent chains of the selected versions
+func SelectedOnlyOption(selectedOnly bool) Option {
+	return func(c *Controller) {
+		c.selectedOnly = selectedOnly
+	}
+}
+
+// ExecutorOption specifies the executor of the external commands
+func ExecutorOption(executor Executor) Option {
+	return func(c *Controller) {
+		c.executor = executor
+	}
+}
+
+// CacheOption specifies if cache the resolved graph and the cache directory
+func CacheOption(enabled bool, cacheDir string) Option {
+	return func(c *Controller) {
+		c.cacheEnabled = enabled
+		c.cacheDir = cacheDir
+	}
+}
+
+// FetchOption specifies if fetch the missing go.mod files from the proxies
+func FetchOption(fetch bool) Option {
+	return func(c *Controller) {
+		c.fetch = fetch
+	}
+}
+
+// IndirectOption specifies if include the indirect requirements
+func IndirectOption(indirect bool) Option {
+	return func(c *Controller) {
+		c.indirect = indirect
+	}
+}
+
+// PruneOption specifies if apply the module graph pruning of go 1.17 and later
+func PruneOption(prune bool) Option {
+	return func(c *Controller) {
+		c.prune = prune
+	}
+}
+
+// ChainLimitOption specifies the max number of the parent chains and the max number of the edges of a chain
+func ChainLimitOption(maxChains, maxDepth int) Option {
+	return func(c *Controller) {
+		c.maxChains = maxChains
+		c.maxDepth = maxDepth
+	}
+}
+
+// ParentFormatOption specifies how the parent chains are printed
+func ParentFormatOption(parentFormat string) Option {
+	return func(c *Controller) {
+		c.parentFormat = parentFormat
+	}
+}
 
-	RootNode *Node
-	m        map[string]*Node
+// KShortestOption specifies the number of the shortest parent chains which are printed
+func KShortestOption(k int) Option {
+	return func(c *Controller) {
+		c.kShortest = k
+	}
+}
+
+// PathPrefixOption specifies the comma-separated module path prefixes of the dependency trees
+func PathPrefixOption(pathPrefix string) Option {
+	return func(c *Controller) {
+		c.pathPrefix = pathPrefix
+	}
+}
+
+// OutputFormatOption specifies the output format, text or json
+func OutputFormatOption(outputFormat string) Option {
+	return func(c *Controller) {
+		c.outputFormat = outputFormat
+	}
 }
 
-func NewController(baseDir string) *Controller {
+// FailOnMultipleMajorsOption specifies if fail when a module is selected at multiple major versions
+func FailOnMultipleMajorsOption(failOnMultipleMajors bool) Option {
+	return func(c *Controller) {
+		c.failOnMultipleMajors = failOnMultipleMajors
+	}
+}
+
+func NewController(baseDir string, options ...Option) *Controller {
 	if baseDir == constant.EmptyString {
 		baseDir = config.DefaultModDir
 	}
-	return &Controller{
-		baseDir: baseDir,
-		m:       make(map[string]*Node),
+	c := &Controller{
+		baseDir:      baseDir,
+		resolverType: config.DefaultModResolver,
+		workers:      config.DefaultModWorkers,
+		parentFormat: config.DefaultModParentFormat,
+		outputFormat: config.DefaultModOutputFormat,
+		executor:     NewOSExecutor(),
+		m:            make(map[string]*Node),
+	}
+
+	for _, option := range options {
+		option(c)
 	}
+
+	return c
 }
 
 func NewControllerWithDefault() *Controller {
@@ -57,46 +227,442 @@ func (c *Controller) Init() error {
 	}
 
 	var err error
-	defaultPackageRootPath, err = getPackageRootPath()
+	defaultPackageRootPath, err = c.getPackageRootPath()
 	if err != nil {
 		return err
 	}
 
-	c.RootNode = NewNode(c.baseDir, constant.EmptyString)
+	directives, err := c.initRootNodes()
+	if err != nil {
+		return err
+	}
 
-	return c.RootNode.Resolve(c.m)
+	if c.fetch {
+		c.proxy, err = c.getProxyClient()
+		if err != nil {
+			return err
+		}
+	}
+
+	var cacheKey string
+	if c.cacheEnabled {
+		cacheKey, err = c.getGraphCacheKey(directives)
+		if err != nil {
+			return err
+		}
+		ok, err := c.loadGraphCache(cacheKey)
+		if err != nil {
+			return err
+		}
+		if ok {
+			log.Debugf("graph is loaded from the cache. key: %s", cacheKey)
+			// the retractions depend on the module cache and the proxies, which are not covered by the cache key
+			return MarkRetracted(defaultPackageRootPath, c.proxy, c.GetAllNodes())
+		}
+	}
+
+	resolver, err := c.getResolver()
+	if err != nil {
+		return err
+	}
+
+	err = ResolveConcurrently(c.getMainRootNodes(), resolver, directives, c.m, c.workers)
+	if err != nil {
+		return err
+	}
+	err = c.resolveNestedRootNodes(resolver)
+	if err != nil {
+		return err
+	}
+
+	c.markSelected(directives.IsWorkspace())
+
+	nodes := c.GetAllNodes()
+
+	err = MarkRetracted(defaultPackageRootPath, c.proxy, nodes)
+	if err != nil {
+		return err
+	}
+
+	if c.cacheEnabled {
+		skippedNodes := getSkippedNodes(nodes)
+		if len(skippedNodes) > constant.ZeroInt {
+			// the skipped modules may be resolved next time, such as they are downloaded, so the incomplete graph is not cached
+			log.Warnf("the requirements of some modules are unknown, the graph is incomplete, will not save the graph cache. modules: %s", strings.Join(skippedNodes, constant.CommaString))
+			return nil
+		}
+		err = c.saveGraphCache(cacheKey)
+		if err != nil {
+			log.Warnf("save graph cache failed, will ignore it. error: %s", err.Error())
+		}
+	}
+
+	return nil
 }
 
+// getSkippedNodes returns the full names of the nodes which requirements are unknown
+func getSkippedNodes(nodes []*Node) []string {
+	var result []string
+	for _, node := range nodes {
+		if node.Skipped {
+			result = append(result, node.FullName)
+		}
+	}
+
+	return result
+}
+
+// initRootNodes initializes the root nodes and returns the directives of the main modules
+func (c *Controller) initRootNodes() (*Directives, error) {
+	workFile, err := c.getWorkFile()
+	if err != nil {
+		return nil, err
+	}
+	if workFile == constant.EmptyString {
+		f, err := parseModFile(filepath.Join(c.baseDir, goModFileName), true)
+		if err != nil {
+			return nil, err
+		}
+		c.RootNode = NewNode(c.baseDir, constant.EmptyString)
+		c.RootNodes = []*Node{c.RootNode}
+		directives := NewDirectives(c.baseDir, f)
+
+		err = c.initNestedRootNodes(directives)
+		if err != nil {
+			return nil, err
+		}
+
+		return directives, nil
+	}
+
+	data, err := os.ReadFile(workFile)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	wf, err := modfile.ParseWork(workFile, data, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(wf.Use) == constant.ZeroInt {
+		return nil, errors.Errorf("Controller.initRootNodes(): there is no use directive in go.work file. go.work: %s", workFile)
+	}
+
+	workDir := filepath.Dir(workFile)
+	directives := NewDirectives(workDir, nil)
+	c.RootNodes = nil
+	var modulePaths []string
+	for _, use := range wf.Use {
+		dir := use.Path
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(workDir, dir)
+		}
+		f, err := parseModFile(filepath.Join(dir, goModFileName), true)
+		if err != nil {
+			return nil, err
+		}
+		if f == nil || f.Module == nil {
+			return nil, errors.Errorf("Controller.initRootNodes(): workspace module does not have a valid go.mod file. directory: %s", dir)
+		}
+		directives.AddModFile(dir, f)
+		c.RootNodes = append(c.RootNodes, NewNode(dir, f.Module.Mod.Path))
+		modulePaths = append(modulePaths, f.Module.Mod.Path)
+	}
+	directives.AddWorkFile(workDir, wf, modulePaths)
+	c.RootNode = c.RootNodes[constant.ZeroInt]
+
+	return directives, nil
+}
+
+// initNestedRootNodes adds a root node for each nested module of the main module
+func (c *Controller) initNestedRootNodes(directives *Directives) error {
+	modDirs, err := findNestedModDirs(c.baseDir)
+	if err != nil {
+		return err
+	}
+	for _, dir := range modDirs {
+		if directives.IsReplaceDir(dir) {
+			continue
+		}
+		f, err := parseModFile(filepath.Join(dir, goModFileName), false)
+		if err != nil {
+			return err
+		}
+		if f == nil || f.Module == nil {
+			log.Warnf("nested module does not have a valid go.mod file, will ignore it. directory: %s", dir)
+			continue
+		}
+		rootNode := NewNode(dir, f.Module.Mod.Path)
+		rootNode.Nested = true
+		c.RootNodes = append(c.RootNodes, rootNode)
+	}
+
+	return nil
+}
+
+// getWorkFile returns the absolute path of the go.work file, it is empty if not in workspace mode
+func (c *Controller) getWorkFile() (string, error) {
+	workFile := c.workFile
+	if workFile == constant.EmptyString {
+		output, err := c.executor.ExecuteCommand(c.baseDir, getGoWorkCommand, false)
+		if err != nil {
+			return constant.EmptyString, errors.Annotatef(err, "output: %s", output)
+		}
+		workFile = strings.TrimSpace(output)
+		if workFile == goWorkOff {
+			return constant.EmptyString, nil
+		}
+	}
+	if workFile == constant.EmptyString {
+		return constant.EmptyString, nil
+	}
+
+	absPath, err := filepath.Abs(workFile)
+
+	return absPath, errors.Trace(err)
+}
+
+// getResolver returns the resolver of the resolver type
+func (c *Controller) getResolver() (Resolver, error) {
+	if c.prune && (c.resolverType == config.ModResolverList || c.resolverType == config.ModResolverGraph) {
+		return nil, errors.Errorf("Controller.getResolver(): pruning is not supported by the resolver as it does not know the go versions of the modules, "+
+			"please use the modfile or vendor resolver. resolver type: %s", c.resolverType)
+	}
+
+	switch c.resolverType {
+	case config.ModResolverModFile:
+		return NewModFileResolver(c.proxy, c.isIndirectIncluded()), nil
+	case config.ModResolverList:
+		return NewListResolver(c.executor, c.isIndirectIncluded()), nil
+	case config.ModResolverVendor:
+		return c.getVendorResolver()
+	case config.ModResolverAuto:
+		if hasVendorModulesFile(c.baseDir) {
+			log.Infof("vendor/modules.txt exists, the vendor resolver is used, as it does not record the requirements between the dependencies, "+
+				"all the vendored modules are the children of the main module, specify the modfile resolver to resolve the whole graph. directory: %s", c.baseDir)
+			return c.getVendorResolver()
+		}
+		return NewModFileResolver(c.proxy, c.isIndirectIncluded()), nil
+	case config.ModResolverGraph:
+		output, err := c.GetGoModGraph()
+		if err != nil {
+			return nil, errors.Annotatef(err, "output: %s", output)
+		}
+		return NewGraphResolver(output)
+	default:
+		return nil, errors.Errorf("Controller.getResolver(): resolver type is not valid. resolver type: %s", c.resolverType)
+	}
+}
+
+// isIndirectIncluded returns if the indirect requirements are included
+func (c *Controller) isIndirectIncluded() bool {
+	return c.indirect || c.prune
+}
+
+// getVendorResolver returns the vendor resolver of the main module
+func (c *Controller) getVendorResolver() (Resolver, error) {
+	resolver, err := NewVendorResolver(filepath.Join(c.baseDir, vendorDirName), c.isIndirectIncluded())
+	if err != nil {
+		return nil, err
+	}
+	f, err := parseModFile(filepath.Join(c.baseDir, goModFileName), true)
+	if err != nil {
+		return nil, err
+	}
+
+	c.vendorInconsistencies = resolver.CheckConsistency(f)
+	for _, inconsistency := range c.vendorInconsistencies {
+		log.Warnf("vendor/modules.txt is inconsistent with go.mod. %s", inconsistency)
+	}
+
+	return resolver, nil
+}
+
+// GetVendorInconsistencies returns the inconsistencies between vendor/modules.txt and go.mod
+func (c *Controller) GetVendorInconsistencies() []string {
+	return c.vendorInconsistencies
+}
+
+// GetAllNodes returns all the nodes in the graph except the root node which does not have a name
+func (c *Controller) GetAllNodes() []*Node {
+	var result []*Node
+
+	for _, node := range c.m {
+		result = append(result, node)
+	}
+	sortNodes(result)
+
+	return result
+}
+
+// GetNodes returns the nodes of given module, the name could be a glob pattern
 func (c *Controller) GetNodes(name, version string) []*Node {
 	var result []*Node
 
 	for _, node := range c.m {
-		if name == node.Name && (version == constant.EmptyString || version == node.Version) {
+		if c.isNameMatched(name, node.Name) && (version == constant.EmptyString || version == node.Version) {
 			result = append(result, node)
 		}
 	}
+	sortNodes(result)
 
 	return result
 }
 
+// isNameMatched returns if the module path matches given name or the name regex
+func (c *Controller) isNameMatched(name, path string) bool {
+	if name == constant.EmptyString && c.nameRegex != nil {
+		return c.nameRegex.MatchString(path)
+	}
+	if isNamePattern(name) {
+		return module.MatchPrefixPatterns(name, path)
+	}
+
+	return name == path
+}
+
+// isNamePattern returns if the name is a glob pattern
+func isNamePattern(name string) bool {
+	return strings.ContainsAny(name, globMetaChars)
+}
+
+// GetModuleNames returns the sorted module paths which match given name
+func (c *Controller) GetModuleNames(name string) []string {
+	var result []string
+
+	for _, node := range c.GetNodes(name, constant.EmptyString) {
+		if len(result) == constant.ZeroInt || result[len(result)-constant.OneInt] != node.Name {
+			result = append(result, node.Name)
+		}
+	}
+
+	return result
+}
+
+// GetParentChain returns the parent chains of the nodes of given module
 func (c *Controller) GetParentChain(name, version string) [][]*Node {
-	nodes := c.GetNodes(name, version)
+	result, _ := c.getParentChainWithLimit(name, version)
+
+	return result
+}
+
+// getParentChainWithLimit returns the parent chains and if any of them are cut off
+func (c *Controller) getParentChainWithLimit(name, version string) ([][]*Node, bool) {
 	var result [][]*Node
 
-	for _, node := range nodes {
-		chain := node.GetParentChain()
+	for _, node := range c.getTargetNodes(name, version) {
+		maxChains := c.maxChains
+		if maxChains > constant.ZeroInt {
+			maxChains -= len(result)
+			if maxChains <= constant.ZeroInt {
+				// the chains are truncated only if the node has any chain
+				chain, _ := node.GetParentChainWithLimit(constant.OneInt, c.maxDepth)
+				if len(chain) > constant.ZeroInt {
+					return result, true
+				}
+				continue
+			}
+		}
+		chain, truncated := node.GetParentChainWithLimit(maxChains, c.maxDepth)
 		result = append(result, chain...)
+		if truncated {
+			return result, true
+		}
+	}
+
+	return result, false
+}
+
+// GetShortestParentChains returns the k shortest parent chains of the nodes of given module
+func (c *Controller) GetShortestParentChains(name, version string, k int) [][]*Node {
+	return GetShortestParentChains(c.getTargetNodes(name, version), k, c.maxDepth)
+}
+
+// GetAncestorGraphs returns the ancestor subgraphs of the nodes of given module
+func (c *Controller) GetAncestorGraphs(name, version string) []*AncestorGraph {
+	var result []*AncestorGraph
+
+	for _, node := range c.getTargetNodes(name, version) {
+		result = append(result, NewAncestorGraph(node))
+	}
+
+	return result
+}
+
+// getTargetNodes returns the nodes of given module
+func (c *Controller) getTargetNodes(name, version string) []*Node {
+	var result []*Node
+
+	for _, node := range c.GetNodes(name, version) {
+		if c.selectedOnly && !c.isSelected(node) {
+			continue
+		}
+		result = append(result, node)
 	}
 
 	return result
 }
 
+// PrintParentChain prints the parent chains of given module
 func (c *Controller) PrintParentChain(name, version string, modUseCompileVersion bool) error {
 	err := c.Init()
 	if err != nil {
 		return err
 	}
 
+	if !isNamePattern(name) && (name != constant.EmptyString || c.nameRegex == nil) {
+		return c.printParentChain(name, version, modUseCompileVersion)
+	}
+
+	for _, moduleName := range c.GetModuleNames(name) {
+		fmt.Printf(moduleHeaderTemplate, moduleName)
+		err = c.printParentChain(moduleName, version, modUseCompileVersion)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// printParentChain prints the parent chains of given module path with the parent format of the controller
+func (c *Controller) printParentChain(name, version string, modUseCompileVersion bool) error {
+	var err error
+
+	v := version
+	if modUseCompileVersion {
+		v, err = c.GetCompileVersion(name)
+		if err != nil {
+			return err
+		}
+	}
+
+	switch c.parentFormat {
+	case config.ModParentFormatCount:
+		c.PrintChainCounts(c.GetAncestorGraphs(name, v))
+	case config.ModParentFormatDAG:
+		c.PrintAncestorGraphs(c.GetAncestorGraphs(name, v))
+	default:
+		if c.kShortest > constant.ZeroInt {
+			c.PrintNodesList(c.GetShortestParentChains(name, v, c.kShortest))
+			return nil
+		}
+		nodesList, truncated := c.getParentChainWithLimit(name, v)
+		c.PrintNodesList(nodesList)
+		if truncated {
+			log.Warnf(maxChainsWarning, c.maxChains)
+		}
+	}
+
+	return nil
+}
+
+// PrintChildTree prints the dependency trees of the nodes of given module
+func (c *Controller) PrintChildTree(name, version string, modUseCompileVersion bool) error {
+	err := c.Init()
+	if err != nil {
+		return err
+	}
+
 	v := version
 	if modUseCompileVersion {
 		v, err = c.GetCompileVersion(name)
@@ -105,12 +671,42 @@ func (c *Controller) PrintParentChain(name, version string, modUseCompileVersion
 		}
 	}
 
-	nodesList := c.GetParentChain(name, v)
-	c.PrintNodesList(nodesList)
+	for _, node := range c.getTargetNodes(name, v) {
+		for _, line := range node.GetChildTreeWithFilter(c.maxDepth, c.getTreeFilter()) {
+			fmt.Println(line)
+		}
+	}
 
 	return nil
 }
 
+// PrintAll prints the dependency trees of all the root nodes
+func (c *Controller) PrintAll() error {
+	err := c.Init()
+	if err != nil {
+		return err
+	}
+
+	for _, rootNode := range c.RootNodes {
+		for _, line := range rootNode.GetChildTreeWithFilter(c.maxDepth, c.getTreeFilter()) {
+			fmt.Println(line)
+		}
+	}
+
+	return nil
+}
+
+// getTreeFilter returns the filter of the path prefixes, it returns nil if they are not specified
+func (c *Controller) getTreeFilter() func(node *Node) bool {
+	if c.pathPrefix == constant.EmptyString {
+		return nil
+	}
+
+	return func(node *Node) bool {
+		return node.Name != constant.EmptyString && module.MatchPrefixPatterns(c.pathPrefix, node.Name)
+	}
+}
+
 func (c *Controller) PrintNodesList(nodesList [][]*Node) {
 	prefix := outputPrefixLast
 	for _, nodes := range nodesList {
@@ -119,18 +715,116 @@ func (c *Controller) PrintNodesList(nodesList [][]*Node) {
 				fmt.Println(node.RootPath)
 				continue
 			}
-			fmt.Printf("%s%s\n", strings.Repeat(constant.SpaceString, defaultSpaceNum*i), prefix+node.String())
+			fmt.Printf("%s%s\n", strings.Repeat(constant.SpaceString, defaultSpaceNum*i), prefix+getChildString(nodes[i-constant.OneInt], node))
+		}
+	}
+}
+
+// PrintChainCounts prints the number of the parent chains of the target nodes per direct dependency
+func (c *Controller) PrintChainCounts(graphs []*AncestorGraph) {
+	for _, graph := range graphs {
+		total, counts := graph.CountParentChains()
+		fmt.Printf(chainCountTemplate, graph.Target.String(), total.String())
+		for i, count := range counts {
+			if i == constant.ZeroInt || count.Top != counts[i-constant.OneInt].Top {
+				fmt.Println(count.Top.RootPath)
+			}
+			prefix := outputPrefix
+			if i == len(counts)-constant.OneInt || count.Top != counts[i+constant.OneInt].Top {
+				prefix = outputPrefixLast
+			}
+			fmt.Printf(chainCountTemplate, prefix+getChildString(count.Top, count.Child), count.Count.String())
+		}
+	}
+}
+
+// PrintAncestorGraphs prints the ancestor subgraphs of the target nodes
+func (c *Controller) PrintAncestorGraphs(graphs []*AncestorGraph) {
+	for _, graph := range graphs {
+		total, _ := graph.CountParentChains()
+		fmt.Printf(chainCountTemplate, graph.Target.String(), total.String())
+		for _, node := range graph.Nodes {
+			if node == graph.Target {
+				continue
+			}
+			output := node.String()
+			if len(node.ParentNodes) == constant.ZeroInt {
+				output = node.RootPath
+			}
+			fmt.Printf(chainCountTemplate, output, graph.Count(node).String())
+			childNodes := graph.GetChildNodes(node)
+			for i, childNode := range childNodes {
+				fmt.Println(getOutputPrefix(i, len(childNodes)) + getChildString(node, childNode))
+			}
 		}
 	}
 }
 
+// getChildString returns the text representation of the child node of the parent node
+func getChildString(parentNode, childNode *Node) string {
+	output := childNode.String()
+	if parentNode.IsIndirectChild(childNode) {
+		output += constant.SpaceString + constant.LeftParenthesisString + indirectString + constant.RightParenthesisString
+	}
+
+	return output
+}
+
+// GetCycles returns all the strongly connected components of the graph which contain cycles
+func (c *Controller) GetCycles() []*Cycle {
+	var nodes []*Node
+	for _, rootNode := range c.RootNodes {
+		if rootNode.FullName == constant.EmptyString {
+			nodes = append(nodes, rootNode)
+		}
+	}
+
+	return FindCycles(append(nodes, c.GetAllNodes()...))
+}
+
+// PrintCycles prints the cycles of the graph
+func (c *Controller) PrintCycles() error {
+	err := c.Init()
+	if err != nil {
+		return err
+	}
+
+	cycles := c.GetCycles()
+	if len(cycles) == constant.ZeroInt {
+		fmt.Println(noCycleMessage)
+		return nil
+	}
+
+	for i, cycle := range cycles {
+		fmt.Printf(cycleHeaderTemplate, i+constant.OneInt, len(cycle.Nodes))
+		for j, node := range cycle.Nodes {
+			fmt.Println(getOutputPrefix(j, len(cycle.Nodes)) + node.String())
+		}
+		fmt.Println(closingEdgesHeader)
+		for j, edge := range cycle.ClosingEdges {
+			fmt.Println(getOutputPrefix(j, len(cycle.ClosingEdges)) + edge.String())
+		}
+	}
+
+	return nil
+}
+
+// getOutputPrefix returns the output prefix of the i-th line of the total lines
+func getOutputPrefix(i, total int) string {
+	if i == total-constant.OneInt {
+		return outputPrefixLast
+	}
+
+	return outputPrefix
+}
+
 func (c *Controller) GetGoModGraph() (string, error) {
-	return linux.ExecuteCommand(goModGraphCommand, linux.WorkDirOption(c.baseDir))
+	return c.executor.ExecuteCommand(c.baseDir, goModGraphCommand, false)
 }
 
 func (c *Controller) GetCompileVersion(name string) (string, error) {
 	command := fmt.Sprintf(goModListCommandTemplate, name)
-	output, err := linux.ExecuteCommand(command, linux.WorkDirOption(c.baseDir))
+	output, err := c.executor.ExecuteCommand(c.baseDir, command, false)
 	if err != nil {
 		return output, err
 	}
@@ -143,8 +837,44 @@ func (c *Controller) GetCompileVersion(name string) (string, error) {
 	return outputList[constant.OneInt], nil
 }
 
-func getPackageRootPath() (string, error) {
-	output, err := linux.ExecuteCommand(getPackageRootPathCommand)
+// getProxyClient returns the proxy client of the go env
+func (c *Controller) getProxyClient() (*ProxyClient, error) {
+	output, err := c.executor.ExecuteCommand(constant.EmptyString, getProxyEnvCommand, false)
+	if err != nil {
+		return nil, errors.Annotatef(err, "output: %s", output)
+	}
+
+	values := strings.Split(strings.TrimRight(output, constant.CRLFString), constant.CRLFString)
+	for len(values) < constant.ThreeInt {
+		values = append(values, constant.EmptyString)
+	}
+
+	pc := NewProxyClient(strings.TrimSpace(values[constant.ZeroInt]), strings.TrimSpace(values[constant.OneInt]), strings.TrimSpace(values[constant.TwoInt]))
+
+	// the fetched go.mod files are verified with the go.sum files of the main modules and go.work.sum
+	var sumFiles []string
+	workFile, err := c.getWorkFile()
+	if err != nil {
+		return nil, err
+	}
+	if workFile != constant.EmptyString {
+		sumFiles = append(sumFiles, workFile+goWorkSumFileExt)
+	}
+	for _, rootNode := range c.RootNodes {
+		sumFiles = append(sumFiles, filepath.Join(rootNode.RootPath, goSumFileName))
+	}
+	for _, sumFile := range sumFiles {
+		err = pc.LoadGoSum(sumFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return pc, nil
+}
+
+func (c *Controller) getPackageRootPath() (string, error) {
+	output, err := c.executor.ExecuteCommand(constant.EmptyString, getPackageRootPathCommand, false)
 	if err != nil {
 		return output, err
 	}
@@ -1,16 +1,51 @@
 package mod
 
 import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 	"testing"
 
+	"github.com/romberli/go-util/constant"
 	"github.com/romberli/log"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/romberli/go-mod/config"
 )
 
 const (
-	testModDir     = "/Users/romber/source_code/go/src/github.com/romberli/go-util"
-	testModName    = "gopkg.in/yaml.v2"
+	testModDir     = testFixtureModDir
+	testModName    = "example.com/c"
 	testModVersion = ""
+
+	testFixtureModDir       = "testdata/root"
+	testFixtureModCacheDir  = "testdata/modcache"
+	testFixtureReplaceDir   = "testdata/replace"
+	testFixtureExcludeDir   = "testdata/exclude"
+	testFixtureWorkspaceDir = "testdata/workspace"
+	testFixtureVendorDir    = "testdata/vendor"
+	testFixtureNestedDir    = "testdata/nested"
+	testFixtureProxyDir     = "testdata/proxy"
+	testFixturePruneDir     = "testdata/prune"
+	testFixtureWhyDir       = "testdata/why"
+	testFixtureGoModGraph   = `example.com/root example.com/a@v1.0.0
+example.com/root example.com/b@v1.1.0
+example.com/root example.com/d@v1.0.0
+example.com/root go@1.21
+example.com/a@v1.0.0 example.com/c@v1.0.0
+example.com/a@v1.0.0 example.com/d@v1.0.0
+example.com/a@v1.0.0 go@1.21
+example.com/b@v1.1.0 example.com/c@v1.2.0
+example.com/b@v1.1.0 example.com/d@v1.0.0
+go@1.21 toolchain@go1.21
+`
 )
 
 var (
@@ -18,11 +53,73 @@ var (
 )
 
 func init() {
-	testController = NewController(testModDir)
+	testController = NewController(testModDir, ExecutorOption(newTestExecutor()))
+}
+
+// newTestExecutor returns a fake executor which uses the fixture module cache and
+// returns the compile version of the fixture module
+func newTestExecutor() *FakeExecutor {
+	modCacheDir, err := filepath.Abs(testFixtureModCacheDir)
+	if err != nil {
+		panic(err)
+	}
+
+	executor := NewFakeExecutor()
+	executor.AddOutput(constant.EmptyString, getPackageRootPathCommand, modCacheDir+constant.CRLFString, nil)
+	executor.AddOutput(constant.EmptyString, fmt.Sprintf(goModListCommandTemplate, testModName), "example.com/c v1.2.0\n", nil)
+	executor.AddOutput(constant.EmptyString, getGoVersionCommand, "go1.22.0\n", nil)
+	executor.AddOutput(constant.EmptyString, getGoWorkCommand, "\n", nil)
+
+	return executor
 }
 
 func TestModController_All(t *testing.T) {
 	TestModController_PrintParentChain(t)
+	TestModController_InitWithModFileResolver(t)
+	TestModController_ResolveWithGraphResolver(t)
+	TestModController_InitWithWorkers(t)
+	TestModController_InitWithReplace(t)
+	TestModController_InitWithExcludeAndRetract(t)
+	TestModController_InitWithWorkspace(t)
+	TestModController_InitWithVendor(t)
+	TestModController_MarkSelected(t)
+	TestModController_InitWithListResolver(t)
+	TestModController_InitWithCache(t)
+	TestModController_GetCycles(t)
+	TestModController_InitWithNestedModules(t)
+	TestModController_InitWithDownloadCache(t)
+	TestModController_InitWithSkippedNodes(t)
+	TestModController_InitWithProxy(t)
+	TestModController_MarkRetractedWithProxy(t)
+	TestModController_InitWithIndirect(t)
+	TestModController_InitWithPrune(t)
+	TestModController_CountParentChains(t)
+	TestModController_GetShortestParentChains(t)
+	TestModController_GetChildTree(t)
+	TestModController_GetChildTreeWithFilter(t)
+	TestModController_GetImportChain(t)
+	TestModController_GetImportChainWithExecutor(t)
+	TestModController_GetModuleVersions(t)
+	TestModController_GetConflicts(t)
+	TestModController_GetMultipleMajors(t)
+	TestModController_GetNodesWithPattern(t)
+}
+
+// newTestController returns a controller which resolves the fixture module with the fixture module cache
+func newTestController(t *testing.T, options ...Option) *Controller {
+	return newTestControllerWithDir(t, testFixtureModDir, options...)
+}
+
+// newTestControllerWithDir returns a controller which resolves given fixture module with the fixture module cache
+func newTestControllerWithDir(t *testing.T, modDir string, options ...Option) *Controller {
+	modCacheDir, err := filepath.Abs(testFixtureModCacheDir)
+	if err != nil {
+		t.Fatalf("get absolute path of fixture module cache failed: %+v", err)
+	}
+	t.Setenv("GOMODCACHE", modCacheDir)
+	t.Setenv("GOWORK", constant.EmptyString)
+
+	return NewController(modDir, options...)
 }
 
 func TestModController_PrintParentChain(t *testing.T) {
@@ -38,3 +135,1030 @@ func TestModController_PrintParentChain(t *testing.T) {
 		log.Errorf("test PrintParentChain() failed: %+v", err)
 	}
 }
+
+func TestModController_InitWithModFileResolver(t *testing.T) {
+	asst := assert.New(t)
+
+	log.SetLevel(log.ErrorLevel)
+
+	c := newTestController(t, ResolverOption(config.ModResolverModFile))
+	err := c.Init()
+	asst.Nil(err, "test InitWithModFileResolver() failed")
+	asst.Equal(2, len(c.RootNode.ChildNodes), "test InitWithModFileResolver() failed")
+	asst.Equal(2, len(c.GetNodes("example.com/c", testModVersion)), "test InitWithModFileResolver() failed")
+	// indirect requirement of the main module should be ignored
+	asst.Equal(1, len(c.GetNodes("example.com/d", testModVersion)), "test InitWithModFileResolver() failed")
+	asst.Equal(2, len(c.GetNodes("example.com/d", testModVersion)[0].ParentNodes), "test InitWithModFileResolver() failed")
+
+	chains := c.GetParentChain("example.com/c", "v1.0.0")
+	asst.Equal(1, len(chains), "test InitWithModFileResolver() failed")
+	asst.Equal("example.com/a@v1.0.0", chains[0][1].FullName, "test InitWithModFileResolver() failed")
+}
+
+func TestModController_ResolveWithGraphResolver(t *testing.T) {
+	asst := assert.New(t)
+
+	resolver, err := NewGraphResolver(testFixtureGoModGraph)
+	asst.Nil(err, "test ResolveWithGraphResolver() failed")
+
+	c := NewController(testFixtureModDir, ResolverOption(config.ModResolverGraph))
+	c.RootNode = NewNode(c.baseDir, constant.EmptyString)
+	err = c.RootNode.Resolve(resolver, c.m)
+	asst.Nil(err, "test ResolveWithGraphResolver() failed")
+	asst.Equal(3, len(c.RootNode.ChildNodes), "test ResolveWithGraphResolver() failed")
+	asst.Equal(5, len(c.m), "test ResolveWithGraphResolver() failed")
+	asst.Equal(3, len(c.GetNodes("example.com/d", testModVersion)[0].ParentNodes), "test ResolveWithGraphResolver() failed")
+	asst.Equal(0, len(c.GetNodes("go", testModVersion)), "test ResolveWithGraphResolver() failed")
+}
+
+// dumpGraph returns the text representation of the graph which contains the edges of all the nodes
+func dumpGraph(c *Controller) string {
+	var lines []string
+	for fullName, node := range c.m {
+		var parents []string
+		for _, parentNode := range node.ParentNodes {
+			parents = append(parents, parentNode.FullName)
+		}
+		var children []string
+		for _, childNode := range node.ChildNodes {
+			children = append(children, childNode.FullName)
+		}
+		lines = append(lines, fmt.Sprintf("%s parents: %v, children: %v", fullName, parents, children))
+	}
+	sort.Strings(lines)
+
+	return strings.Join(lines, constant.CRLFString)
+}
+
+func TestModController_InitWithWorkers(t *testing.T) {
+	asst := assert.New(t)
+
+	log.SetLevel(log.ErrorLevel)
+
+	c := newTestController(t, WorkersOption(1))
+	err := c.Init()
+	asst.Nil(err, "test InitWithWorkers() failed")
+	expected := dumpGraph(c)
+
+	for i := 0; i < 10; i++ {
+		c = newTestController(t, WorkersOption(8))
+		err = c.Init()
+		asst.Nil(err, "test InitWithWorkers() failed")
+		asst.Equal(expected, dumpGraph(c), "test InitWithWorkers() failed")
+	}
+}
+
+func TestModController_InitWithReplace(t *testing.T) {
+	asst := assert.New(t)
+
+	log.SetLevel(log.ErrorLevel)
+
+	c := newTestControllerWithDir(t, testFixtureReplaceDir)
+	err := c.Init()
+	asst.Nil(err, "test InitWithReplace() failed")
+
+	// local path replacement
+	nodes := c.GetNodes("example.com/a", testModVersion)
+	asst.Equal(1, len(nodes), "test InitWithReplace() failed")
+	asst.True(nodes[0].IsReplaced(), "test InitWithReplace() failed")
+	asst.Equal("./a", nodes[0].ReplaceName, "test InitWithReplace() failed")
+	asst.Equal("example.com/a@v1.0.0 => ./a", nodes[0].String(), "test InitWithReplace() failed")
+	asst.Equal("example.com/c@v1.2.0", nodes[0].ChildNodes[0].FullName, "test InitWithReplace() failed")
+
+	// module replacement
+	nodes = c.GetNodes("example.com/b", testModVersion)
+	asst.Equal(1, len(nodes), "test InitWithReplace() failed")
+	asst.Equal("example.com/b@v1.1.0 => example.com/e v1.0.0", nodes[0].String(), "test InitWithReplace() failed")
+	asst.Equal("example.com/d@v1.0.0", nodes[0].ChildNodes[0].FullName, "test InitWithReplace() failed")
+	asst.False(c.GetNodes("example.com/d", testModVersion)[0].IsReplaced(), "test InitWithReplace() failed")
+}
+
+func TestModController_InitWithExcludeAndRetract(t *testing.T) {
+	asst := assert.New(t)
+
+	log.SetLevel(log.ErrorLevel)
+
+	c := newTestControllerWithDir(t, testFixtureExcludeDir)
+	err := c.Init()
+	asst.Nil(err, "test InitWithExcludeAndRetract() failed")
+	// excluded version should be ignored
+	asst.Equal(0, len(c.GetNodes("example.com/d", testModVersion)), "test InitWithExcludeAndRetract() failed")
+
+	// the latest release version retracts v1.0.0, the pre-release version should be ignored
+	nodes := c.GetNodes("example.com/c", testModVersion)
+	asst.Equal(2, len(nodes), "test InitWithExcludeAndRetract() failed")
+	asst.True(nodes[0].Retracted, "test InitWithExcludeAndRetract() failed")
+	asst.Equal("contains a critical bug", nodes[0].RetractRationale, "test InitWithExcludeAndRetract() failed")
+	asst.Equal("example.com/c@v1.0.0 (retracted: contains a critical bug) (superseded by v1.2.0)", nodes[0].String(), "test InitWithExcludeAndRetract() failed")
+	asst.False(nodes[1].Retracted, "test InitWithExcludeAndRetract() failed")
+}
+
+func TestModController_InitWithWorkspace(t *testing.T) {
+	asst := assert.New(t)
+
+	log.SetLevel(log.ErrorLevel)
+
+	c := newTestControllerWithDir(t, testFixtureWorkspaceDir)
+	err := c.Init()
+	asst.Nil(err, "test InitWithWorkspace() failed")
+	asst.Equal(2, len(c.RootNodes), "test InitWithWorkspace() failed")
+	asst.Equal("example.com/x", c.RootNode.FullName, "test InitWithWorkspace() failed")
+
+	// the requirement on the workspace module should be resolved to the workspace module itself
+	nodes := c.GetNodes("example.com/y", testModVersion)
+	asst.Equal(1, len(nodes), "test InitWithWorkspace() failed")
+	asst.True(nodes[0].IsRoot(), "test InitWithWorkspace() failed")
+	asst.Equal(c.RootNode, nodes[0].ParentNodes[0], "test InitWithWorkspace() failed")
+
+	// the replace directive of the go.work file should be applied
+	nodes = c.GetNodes("example.com/b", testModVersion)
+	asst.Equal(1, len(nodes), "test InitWithWorkspace() failed")
+	asst.Equal("example.com/e", nodes[0].ReplaceName, "test InitWithWorkspace() failed")
+
+	var roots []string
+	for _, chain := range c.GetParentChain("example.com/d", testModVersion) {
+		roots = append(roots, chain[0].FullName)
+	}
+	asst.Equal([]string{"example.com/x", "example.com/x"}, roots, "test InitWithWorkspace() failed")
+
+	// the go.work file is resolved by go env GOWORK, which honors go env -w as well
+	workFile, err := filepath.Abs(filepath.Join(testFixtureWorkspaceDir, "go.work"))
+	asst.Nil(err, "test InitWithWorkspace() failed")
+	executor := NewFakeExecutor()
+	executor.AddOutput(constant.EmptyString, getGoWorkCommand, workFile+constant.CRLFString, nil)
+	c = NewController(testFixtureModDir, ExecutorOption(executor))
+	resolved, err := c.getWorkFile()
+	asst.Nil(err, "test InitWithWorkspace() failed")
+	asst.Equal(workFile, resolved, "test InitWithWorkspace() failed")
+	executor.AddOutput(constant.EmptyString, getGoWorkCommand, goWorkOff+constant.CRLFString, nil)
+	resolved, err = c.getWorkFile()
+	asst.Nil(err, "test InitWithWorkspace() failed")
+	asst.Equal(constant.EmptyString, resolved, "test InitWithWorkspace() failed")
+}
+
+func TestModController_InitWithVendor(t *testing.T) {
+	asst := assert.New(t)
+
+	log.SetLevel(log.ErrorLevel)
+
+	c := newTestControllerWithDir(t, testFixtureVendorDir)
+	// the module cache is not needed at all
+	t.Setenv("GOMODCACHE", t.TempDir())
+	err := c.Init()
+	asst.Nil(err, "test InitWithVendor() failed")
+	// the vendored modules which are not marked as explicit are indirect
+	asst.Equal(3, len(c.RootNode.ChildNodes), "test InitWithVendor() failed")
+	asst.Equal(0, len(c.GetNodes("example.com/c", testModVersion)), "test InitWithVendor() failed")
+
+	c = newTestControllerWithDir(t, testFixtureVendorDir, IndirectOption(true))
+	t.Setenv("GOMODCACHE", t.TempDir())
+	err = c.Init()
+	asst.Nil(err, "test InitWithVendor() failed")
+	// the go.mod files of the vendored modules are not vendored since go 1.17
+	asst.Equal(4, len(c.RootNode.ChildNodes), "test InitWithVendor() failed")
+	asst.Equal(0, len(c.GetNodes("example.com/a", testModVersion)[0].ChildNodes), "test InitWithVendor() failed")
+	cNode := c.GetNodes("example.com/c", testModVersion)[0]
+	asst.True(c.RootNode.IsIndirectChild(cNode), "test InitWithVendor() failed")
+	asst.False(c.RootNode.IsIndirectChild(c.GetNodes("example.com/a", testModVersion)[0]), "test InitWithVendor() failed")
+	asst.Equal("1.21", cNode.GoVersion, "test InitWithVendor() failed")
+	asst.Equal("example.com/e", c.GetNodes("example.com/b", testModVersion)[0].ReplaceName, "test InitWithVendor() failed")
+
+	inconsistencies := c.GetVendorInconsistencies()
+	asst.Equal([]string{
+		"example.com/b@v1.1.0 is explicitly required in go.mod, but vendor/modules.txt indicates example.com/b@v1.2.0",
+		"example.com/f@v1.0.0 is marked as explicit in vendor/modules.txt, but not explicitly required in go.mod",
+	}, inconsistencies, "test InitWithVendor() failed")
+}
+
+func TestModController_MarkSelected(t *testing.T) {
+	asst := assert.New(t)
+
+	log.SetLevel(log.ErrorLevel)
+
+	c := newTestController(t, SelectedOnlyOption(true))
+	err := c.Init()
+	asst.Nil(err, "test MarkSelected() failed")
+
+	nodes := c.GetNodes("example.com/c", testModVersion)
+	asst.Equal(2, len(nodes), "test MarkSelected() failed")
+	asst.False(nodes[0].Selected, "test MarkSelected() failed")
+	asst.True(nodes[0].IsSuperseded(), "test MarkSelected() failed")
+	asst.Equal("v1.2.0", nodes[0].SelectedVersion, "test MarkSelected() failed")
+	asst.Contains(nodes[0].String(), "(superseded by v1.2.0)", "test MarkSelected() failed")
+	asst.True(nodes[1].Selected, "test MarkSelected() failed")
+	asst.Equal("example.com/c@v1.2.0", nodes[1].String(), "test MarkSelected() failed")
+
+	// only the chains which lead to the selected version should be returned
+	chains := c.GetParentChain("example.com/c", testModVersion)
+	asst.Equal(1, len(chains), "test MarkSelected() failed")
+	asst.Equal("example.com/b@v1.1.0", chains[0][1].FullName, "test MarkSelected() failed")
+}
+
+func TestModController_InitWithListResolver(t *testing.T) {
+	asst := assert.New(t)
+
+	log.SetLevel(log.ErrorLevel)
+
+	rootDir, err := filepath.Abs(testFixtureModDir)
+	asst.Nil(err, "test InitWithListResolver() failed")
+	modCacheDir, err := filepath.Abs(testFixtureModCacheDir)
+	asst.Nil(err, "test InitWithListResolver() failed")
+	aDir := filepath.Join(modCacheDir, "example.com/a@v1.0.0")
+	bDir := filepath.Join(modCacheDir, "example.com/b@v1.1.0")
+
+	executor := newTestExecutor()
+	executor.AddOutput(rootDir, getPackagesCommand, "example.com/a@v1.0.0\nexample.com/b@v1.1.0\n", nil)
+	executor.AddOutput(aDir, getPackagesCommand, "example.com/c@v1.2.0\n", nil)
+	executor.AddOutput(bDir, getPackagesCommand, constant.EmptyString, nil)
+	executor.AddOutput(filepath.Join(modCacheDir, "example.com/c@v1.2.0"), getPackagesCommand, constant.EmptyString, nil)
+
+	c := NewController(testFixtureModDir, ResolverOption(config.ModResolverList), ExecutorOption(executor))
+	err = c.Init()
+	asst.Nil(err, "test InitWithListResolver() failed")
+	asst.Equal(3, len(c.GetAllNodes()), "test InitWithListResolver() failed")
+	asst.Equal(0, len(c.GetNodes("example.com/b", testModVersion)[0].ChildNodes), "test InitWithListResolver() failed")
+	asst.Equal("example.com/a@v1.0.0", c.GetNodes("example.com/c", testModVersion)[0].ParentNodes[0].FullName, "test InitWithListResolver() failed")
+	asst.Contains(executor.GetCommands(), getPackagesCommand, "test InitWithListResolver() failed")
+}
+
+func TestModController_InitWithCache(t *testing.T) {
+	asst := assert.New(t)
+
+	log.SetLevel(log.ErrorLevel)
+
+	cacheDir := t.TempDir()
+	c := NewController(testFixtureReplaceDir, ExecutorOption(newTestExecutor()), CacheOption(true, cacheDir))
+	err := c.Init()
+	asst.Nil(err, "test InitWithCache() failed")
+	expected := dumpGraph(c)
+	files, err := filepath.Glob(filepath.Join(cacheDir, "*"+graphCacheFileExt))
+	asst.Nil(err, "test InitWithCache() failed")
+	asst.Equal(1, len(files), "test InitWithCache() failed")
+
+	// the graph should be loaded from the cache
+	c = NewController(testFixtureReplaceDir, ExecutorOption(newTestExecutor()), CacheOption(true, cacheDir))
+	directives, err := initTestRootNodes(c)
+	asst.Nil(err, "test InitWithCache() failed")
+	key, err := c.getGraphCacheKey(directives)
+	asst.Nil(err, "test InitWithCache() failed")
+	ok, err := c.loadGraphCache(key)
+	asst.Nil(err, "test InitWithCache() failed")
+	asst.True(ok, "test InitWithCache() failed")
+	asst.Equal(expected, dumpGraph(c), "test InitWithCache() failed")
+	asst.Equal("example.com/a@v1.0.0 => ./a", c.GetNodes("example.com/a", testModVersion)[0].String(), "test InitWithCache() failed")
+
+	// the cache key should change if the resolver type changes
+	c = NewController(testFixtureReplaceDir, ExecutorOption(newTestExecutor()), ResolverOption(config.ModResolverGraph), CacheOption(true, cacheDir))
+	directives, err = initTestRootNodes(c)
+	asst.Nil(err, "test InitWithCache() failed")
+	otherKey, err := c.getGraphCacheKey(directives)
+	asst.Nil(err, "test InitWithCache() failed")
+	asst.NotEqual(key, otherKey, "test InitWithCache() failed")
+
+	// the retractions are marked again after the graph is loaded from the cache
+	otherCacheDir := t.TempDir()
+	c = NewController(testFixtureModDir, ExecutorOption(newTestExecutor()), CacheOption(true, otherCacheDir))
+	err = c.Init()
+	asst.Nil(err, "test InitWithCache() failed")
+	asst.True(c.GetNodes("example.com/c", "v1.0.0")[0].Retracted, "test InitWithCache() failed")
+	files, err = filepath.Glob(filepath.Join(otherCacheDir, "*"+graphCacheFileExt))
+	asst.Nil(err, "test InitWithCache() failed")
+	asst.Equal(1, len(files), "test InitWithCache() failed")
+	data, err := os.ReadFile(files[0])
+	asst.Nil(err, "test InitWithCache() failed")
+	asst.Contains(string(data), `"Retracted":true`, "test InitWithCache() failed")
+	err = os.WriteFile(files[0], []byte(strings.ReplaceAll(string(data), `"Retracted":true`, `"Retracted":false`)), constant.DefaultFileMode)
+	asst.Nil(err, "test InitWithCache() failed")
+	c = NewController(testFixtureModDir, ExecutorOption(newTestExecutor()), CacheOption(true, otherCacheDir))
+	err = c.Init()
+	asst.Nil(err, "test InitWithCache() failed")
+	asst.True(c.GetNodes("example.com/c", "v1.0.0")[0].Retracted, "test InitWithCache() failed")
+
+	// the cache key should change if the local path replacement of a nested module changes
+	baseDir := t.TempDir()
+	modFiles := map[string]string{
+		"main/go.mod":       "module example.com/main\n\ngo 1.21\n",
+		"main/tools/go.mod": "module example.com/main/tools\n\ngo 1.21\n\nrequire example.com/e v1.0.0\n\nreplace example.com/e => ../../e\n",
+		"e/go.mod":          "module example.com/e\n\ngo 1.21\n",
+	}
+	for file, content := range modFiles {
+		path := filepath.Join(baseDir, filepath.FromSlash(file))
+		asst.Nil(os.MkdirAll(filepath.Dir(path), constant.DefaultExecFileMode), "test InitWithCache() failed")
+		asst.Nil(os.WriteFile(path, []byte(content), constant.DefaultFileMode), "test InitWithCache() failed")
+	}
+	c = NewController(filepath.Join(baseDir, "main"), ExecutorOption(newTestExecutor()), CacheOption(true, cacheDir))
+	directives, err = initTestRootNodes(c)
+	asst.Nil(err, "test InitWithCache() failed")
+	key, err = c.getGraphCacheKey(directives)
+	asst.Nil(err, "test InitWithCache() failed")
+	err = os.WriteFile(filepath.Join(baseDir, "e", goModFileName), []byte("module example.com/e\n\ngo 1.21\n\nrequire example.com/d v1.0.0\n"), constant.DefaultFileMode)
+	asst.Nil(err, "test InitWithCache() failed")
+	otherKey, err = c.getGraphCacheKey(directives)
+	asst.Nil(err, "test InitWithCache() failed")
+	asst.NotEqual(key, otherKey, "test InitWithCache() failed")
+
+	err = CleanGraphCache(cacheDir)
+	asst.Nil(err, "test InitWithCache() failed")
+	files, err = filepath.Glob(filepath.Join(cacheDir, "*"+graphCacheFileExt))
+	asst.Nil(err, "test InitWithCache() failed")
+	asst.Equal(0, len(files), "test InitWithCache() failed")
+}
+
+// initTestRootNodes initializes the root nodes of the controller as Controller.Init() does
+func initTestRootNodes(c *Controller) (*Directives, error) {
+	var err error
+	c.baseDir, err = filepath.Abs(c.baseDir)
+	if err != nil {
+		return nil, err
+	}
+	defaultPackageRootPath, err = c.getPackageRootPath()
+	if err != nil {
+		return nil, err
+	}
+
+	return c.initRootNodes()
+}
+
+// newTestCycleController returns a controller with the graph: root -> a -> b -> c -> a, c -> c, root -> d -> b, a -> d -> a
+func newTestCycleController() *Controller {
+	c := NewController(testFixtureModDir)
+	c.RootNode = NewNode(testFixtureModDir, constant.EmptyString)
+	c.RootNodes = []*Node{c.RootNode}
+	link := func(parent, child *Node) {
+		parent.AddChildNode(child)
+		child.AddParentNode(parent)
+	}
+	for _, fullName := range []string{"example.com/a@v1.0.0", "example.com/b@v1.0.0", "example.com/c@v1.0.0", "example.com/d@v1.0.0"} {
+		c.m[fullName] = NewNode(testFixtureModCacheDir, fullName)
+	}
+	a, b, cc, d := c.m["example.com/a@v1.0.0"], c.m["example.com/b@v1.0.0"], c.m["example.com/c@v1.0.0"], c.m["example.com/d@v1.0.0"]
+	link(c.RootNode, a)
+	link(c.RootNode, d)
+	link(a, b)
+	link(b, cc)
+	link(cc, a)
+	link(cc, cc)
+	link(d, b)
+	link(a, d)
+	link(d, a)
+
+	return c
+}
+
+func TestModController_GetCycles(t *testing.T) {
+	asst := assert.New(t)
+
+	c := newTestCycleController()
+
+	// the parent chains should not loop forever
+	var chains []string
+	for _, chain := range c.GetParentChain("example.com/c", testModVersion) {
+		var names []string
+		for _, node := range chain[1:] {
+			names = append(names, node.FullName)
+		}
+		chains = append(chains, strings.Join(names, edgeArrowString))
+	}
+	asst.Equal([]string{
+		"example.com/a@v1.0.0 -> example.com/b@v1.0.0 -> example.com/c@v1.0.0",
+		"example.com/d@v1.0.0 -> example.com/a@v1.0.0 -> example.com/b@v1.0.0 -> example.com/c@v1.0.0",
+		"example.com/d@v1.0.0 -> example.com/b@v1.0.0 -> example.com/c@v1.0.0",
+		"example.com/a@v1.0.0 -> example.com/d@v1.0.0 -> example.com/b@v1.0.0 -> example.com/c@v1.0.0",
+	}, chains, "test GetCycles() failed")
+
+	// the counts of the chains are the same as the numbers of the enumerated chains
+	for _, name := range []string{"example.com/a", "example.com/b", "example.com/c"} {
+		for _, graph := range c.GetAncestorGraphs(name, testModVersion) {
+			total, _ := graph.CountParentChains()
+			asst.Equal(len(c.GetParentChain(name, testModVersion)), int(total.Int64()), "test GetCycles() failed "+name)
+		}
+	}
+
+	cycles := c.GetCycles()
+	asst.Equal(1, len(cycles), "test GetCycles() failed")
+	asst.Equal(4, len(cycles[0].Nodes), "test GetCycles() failed")
+	var edges []string
+	for _, edge := range cycles[0].ClosingEdges {
+		edges = append(edges, edge.String())
+	}
+	asst.Equal([]string{"example.com/c@v1.0.0 -> example.com/a@v1.0.0", "example.com/c@v1.0.0 -> example.com/c@v1.0.0", "example.com/d@v1.0.0 -> example.com/a@v1.0.0"}, edges, "test GetCycles() failed")
+}
+
+func TestModController_InitWithNestedModules(t *testing.T) {
+	asst := assert.New(t)
+
+	log.SetLevel(log.ErrorLevel)
+
+	c := newTestControllerWithDir(t, testFixtureNestedDir)
+	err := c.Init()
+	asst.Nil(err, "test InitWithNestedModules() failed")
+	// the modules in testdata and _examples directories are ignored
+	asst.Equal(2, len(c.RootNodes), "test InitWithNestedModules() failed")
+	asst.Equal("example.com/nested/tools", c.RootNodes[1].FullName, "test InitWithNestedModules() failed")
+	asst.Equal(0, len(c.GetNodes("example.com/b", testModVersion)), "test InitWithNestedModules() failed")
+
+	// the requirements of the nested module are not merged into the main module
+	nodes := c.GetNodes("example.com/e", testModVersion)
+	asst.Equal(1, len(nodes), "test InitWithNestedModules() failed")
+	asst.Equal(1, len(nodes[0].ParentNodes), "test InitWithNestedModules() failed")
+	asst.Equal(c.RootNodes[1], nodes[0].ParentNodes[0], "test InitWithNestedModules() failed")
+	for _, childNode := range c.RootNode.ChildNodes {
+		asst.Equal("example.com/a@v1.0.0", childNode.FullName, "test InitWithNestedModules() failed")
+	}
+
+	// the nested module shares example.com/a with the main module, and requires a higher version of example.com/c,
+	// which does not affect the main module
+	asst.True(c.RootNodes[1].Nested, "test InitWithNestedModules() failed")
+	cNode, otherNode := c.GetNodes("example.com/c", "v1.0.0")[0], c.GetNodes("example.com/c", "v1.2.0")[0]
+	asst.True(cNode.Selected, "test InitWithNestedModules() failed")
+	asst.False(otherNode.Selected, "test InitWithNestedModules() failed")
+	asst.Equal("v1.2.0", c.RootNodes[1].SelectedVersions["example.com/c"], "test InitWithNestedModules() failed")
+	asst.Equal("v1.0.0", c.RootNodes[1].SelectedVersions["example.com/a"], "test InitWithNestedModules() failed")
+	asst.True(c.isSelected(otherNode), "test InitWithNestedModules() failed")
+	c.selectedOnly = true
+	asst.Equal(2, len(c.getTargetNodes("example.com/c", constant.EmptyString)), "test InitWithNestedModules() failed")
+	c.selectedOnly = false
+	asst.Equal(0, len(c.GetConflicts()), "test InitWithNestedModules() failed")
+	asst.Equal(0, len(c.GetMultipleMajors()), "test InitWithNestedModules() failed")
+
+	c = newTestControllerWithDir(t, testFixtureNestedDir, PruneOption(true))
+	err = c.Init()
+	asst.Nil(err, "test InitWithNestedModules() failed")
+	asst.True(c.GetNodes("example.com/c", "v1.0.0")[0].Selected, "test InitWithNestedModules() failed")
+	asst.False(c.GetNodes("example.com/c", "v1.2.0")[0].Pruned, "test InitWithNestedModules() failed")
+	asst.Equal("v1.2.0", c.RootNodes[1].SelectedVersions["example.com/c"], "test InitWithNestedModules() failed")
+}
+
+func TestModController_InitWithDownloadCache(t *testing.T) {
+	asst := assert.New(t)
+
+	log.SetLevel(log.ErrorLevel)
+
+	// the module cache only has the go.mod files in the download cache,
+	// except that the source tree of example.com/a is extracted with a different go.mod file
+	modCacheDir := t.TempDir()
+	modFiles := map[string]string{
+		"cache/download/example.com/a/@v/v1.0.0.mod": "module example.com/a\n\nrequire example.com/c v1.0.0\n",
+		"cache/download/example.com/b/@v/v1.1.0.mod": "module example.com/b\n\nrequire example.com/d v1.0.0\n",
+		"example.com/a@v1.0.0/go.mod":                "module example.com/a\n\nrequire example.com/e v1.0.0\n",
+	}
+	for file, content := range modFiles {
+		path := filepath.Join(modCacheDir, filepath.FromSlash(file))
+		asst.Nil(os.MkdirAll(filepath.Dir(path), constant.DefaultExecFileMode), "test InitWithDownloadCache() failed")
+		asst.Nil(os.WriteFile(path, []byte(content), constant.DefaultFileMode), "test InitWithDownloadCache() failed")
+	}
+
+	executor := NewFakeExecutor()
+	executor.AddOutput(constant.EmptyString, getPackageRootPathCommand, modCacheDir+constant.CRLFString, nil)
+	executor.AddOutput(constant.EmptyString, getGoWorkCommand, "\n", nil)
+
+	c := NewController(testFixtureModDir, ResolverOption(config.ModResolverModFile), ExecutorOption(executor))
+	err := c.Init()
+	asst.Nil(err, "test InitWithDownloadCache() failed")
+	asst.Equal(0, len(c.GetNodes("example.com/e", testModVersion)), "test InitWithDownloadCache() failed")
+	asst.Equal("example.com/a@v1.0.0", c.GetNodes("example.com/c", testModVersion)[0].ParentNodes[0].FullName, "test InitWithDownloadCache() failed")
+	asst.Equal("example.com/b@v1.1.0", c.GetNodes("example.com/d", testModVersion)[0].ParentNodes[0].FullName, "test InitWithDownloadCache() failed")
+}
+
+func TestModController_InitWithSkippedNodes(t *testing.T) {
+	asst := assert.New(t)
+
+	log.SetLevel(log.ErrorLevel)
+
+	// the go.mod files of example.com/c and example.com/d are missing in the module cache
+	modCacheDir := t.TempDir()
+	modFiles := map[string]string{
+		"cache/download/example.com/a/@v/v1.0.0.mod": "module example.com/a\n\nrequire example.com/c v1.0.0\n",
+		"cache/download/example.com/b/@v/v1.1.0.mod": "module example.com/b\n\nrequire example.com/d v1.0.0\n",
+	}
+	for file, content := range modFiles {
+		path := filepath.Join(modCacheDir, filepath.FromSlash(file))
+		asst.Nil(os.MkdirAll(filepath.Dir(path), constant.DefaultExecFileMode), "test InitWithSkippedNodes() failed")
+		asst.Nil(os.WriteFile(path, []byte(content), constant.DefaultFileMode), "test InitWithSkippedNodes() failed")
+	}
+
+	executor := newTestExecutor()
+	executor.AddOutput(constant.EmptyString, getPackageRootPathCommand, modCacheDir+constant.CRLFString, nil)
+
+	cacheDir := t.TempDir()
+	c := NewController(testFixtureModDir, ResolverOption(config.ModResolverModFile), ExecutorOption(executor), CacheOption(true, cacheDir))
+	err := c.Init()
+	asst.Nil(err, "test InitWithSkippedNodes() failed")
+	asst.True(c.GetNodes("example.com/c", testModVersion)[0].Skipped, "test InitWithSkippedNodes() failed")
+	asst.False(c.GetNodes("example.com/a", testModVersion)[0].Skipped, "test InitWithSkippedNodes() failed")
+
+	// the incomplete graph should not be cached
+	files, err := filepath.Glob(filepath.Join(cacheDir, "*"+graphCacheFileExt))
+	asst.Nil(err, "test InitWithSkippedNodes() failed")
+	asst.Equal(0, len(files), "test InitWithSkippedNodes() failed")
+}
+
+func TestModController_InitWithProxy(t *testing.T) {
+	asst := assert.New(t)
+
+	log.SetLevel(log.ErrorLevel)
+
+	proxyDir, err := filepath.Abs(testFixtureProxyDir)
+	asst.Nil(err, "test InitWithProxy() failed")
+	executor := NewFakeExecutor()
+	executor.AddOutput(constant.EmptyString, getPackageRootPathCommand, t.TempDir()+constant.CRLFString, nil)
+	executor.AddOutput(constant.EmptyString, getProxyEnvCommand, "file://"+filepath.ToSlash(proxyDir)+"\n\n\n", nil)
+	executor.AddOutput(constant.EmptyString, getGoWorkCommand, "\n", nil)
+
+	// the go.mod files are missing in the module cache, so they are fetched from the file proxy
+	c := NewController(testFixtureModDir, ResolverOption(config.ModResolverModFile), ExecutorOption(executor), FetchOption(true))
+	err = c.Init()
+	asst.Nil(err, "test InitWithProxy() failed")
+	asst.Equal(2, len(c.GetNodes("example.com/c", testModVersion)), "test InitWithProxy() failed")
+	asst.Equal(2, len(c.GetNodes("example.com/d", testModVersion)[0].ParentNodes), "test InitWithProxy() failed")
+
+	// the fetched go.mod files are verified with go.sum, and only the verified ones are written to the module cache
+	goMod, err := os.ReadFile(filepath.Join(testFixtureModDir, goModFileName))
+	asst.Nil(err, "test InitWithProxy() failed")
+	newProxyController := func(goProxy, goSum string) (*Controller, string) {
+		modDir := t.TempDir()
+		asst.Nil(os.WriteFile(filepath.Join(modDir, goModFileName), goMod, constant.DefaultFileMode), "test InitWithProxy() failed")
+		asst.Nil(os.WriteFile(filepath.Join(modDir, goSumFileName), []byte(goSum), constant.DefaultFileMode), "test InitWithProxy() failed")
+		modCacheDir := t.TempDir()
+		executor := NewFakeExecutor()
+		executor.AddOutput(constant.EmptyString, getPackageRootPathCommand, modCacheDir+constant.CRLFString, nil)
+		executor.AddOutput(constant.EmptyString, getProxyEnvCommand, goProxy+"\n\n\n", nil)
+		executor.AddOutput(constant.EmptyString, getGoWorkCommand, "\n", nil)
+
+		return NewController(modDir, ResolverOption(config.ModResolverModFile), ExecutorOption(executor), FetchOption(true)), modCacheDir
+	}
+	fileProxy := "file://" + filepath.ToSlash(proxyDir)
+	c, modCacheDir := newProxyController(fileProxy, "example.com/a v1.0.0/go.mod h1:q6xAZf8JvuovzI5TtByy61HV0fIlQ9UwLM3TMzMF59E=\n")
+	err = c.Init()
+	asst.Nil(err, "test InitWithProxy() failed")
+	_, err = os.Stat(filepath.Join(modCacheDir, "cache/download/example.com/a/@v/v1.0.0.mod"))
+	asst.Nil(err, "test InitWithProxy() failed")
+	_, err = os.Stat(filepath.Join(modCacheDir, "cache/download/example.com/b/@v/v1.1.0.mod"))
+	asst.True(os.IsNotExist(err), "test InitWithProxy() failed")
+
+	// the go.mod file which mismatches go.sum should fail the resolution
+	c, _ = newProxyController(fileProxy, "example.com/b v1.1.0/go.mod h1:q6xAZf8JvuovzI5TtByy61HV0fIlQ9UwLM3TMzMF59E=\n")
+	err = c.Init()
+	asst.NotNil(err, "test InitWithProxy() failed")
+
+	// the modules which fail to fetch are skipped
+	c, _ = newProxyController(proxyOff, constant.EmptyString)
+	err = c.Init()
+	asst.Nil(err, "test InitWithProxy() failed")
+	asst.True(c.GetNodes("example.com/a", testModVersion)[0].Skipped, "test InitWithProxy() failed")
+
+	// the next proxy is tried on any error if the proxy is followed by a pipe
+	brokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer brokenServer.Close()
+	server := httptest.NewServer(http.FileServer(http.Dir(proxyDir)))
+	defer server.Close()
+
+	proxy := NewProxyClient(brokenServer.URL+"|"+server.URL, constant.EmptyString, constant.EmptyString)
+	versions, err := proxy.List("example.com/b")
+	asst.Nil(err, "test InitWithProxy() failed")
+	asst.Equal([]string{"v1.1.0"}, versions, "test InitWithProxy() failed")
+	info, err := proxy.Info("example.com/b", "v1.1.0")
+	asst.Nil(err, "test InitWithProxy() failed")
+	asst.Equal("v1.1.0", info.Version, "test InitWithProxy() failed")
+	data, err := proxy.GoMod("example.com/c", "v1.0.0")
+	asst.Nil(err, "test InitWithProxy() failed")
+	asst.Nil(data, "test InitWithProxy() failed")
+
+	// the next proxy is only tried on not found errors if the proxy is followed by a comma
+	proxy = NewProxyClient(brokenServer.URL+","+server.URL, constant.EmptyString, constant.EmptyString)
+	_, err = proxy.GoMod("example.com/b", "v1.1.0")
+	asst.NotNil(err, "test InitWithProxy() failed")
+
+	// the modules which match GOPRIVATE are not fetched from the proxies
+	proxy = NewProxyClient(server.URL, constant.EmptyString, "example.com/a,example.com/x")
+	data, err = proxy.GoMod("example.com/a", "v1.0.0")
+	asst.Nil(err, "test InitWithProxy() failed")
+	asst.Nil(data, "test InitWithProxy() failed")
+	data, err = proxy.GoMod("example.com/b", "v1.1.0")
+	asst.Nil(err, "test InitWithProxy() failed")
+	asst.NotNil(data, "test InitWithProxy() failed")
+
+	// GOPROXY=off disables the module lookup
+	proxy = NewProxyClient(proxyOff, constant.EmptyString, constant.EmptyString)
+	_, err = proxy.GoMod("example.com/b", "v1.1.0")
+	asst.NotNil(err, "test InitWithProxy() failed")
+}
+
+func TestModController_MarkRetractedWithProxy(t *testing.T) {
+	asst := assert.New(t)
+
+	log.SetLevel(log.ErrorLevel)
+
+	// only example.com/g v1.0.0 is in the module cache, and v1.1.0 which retracts it is only in the proxy
+	modCacheDir := t.TempDir()
+	path := filepath.Join(modCacheDir, filepath.FromSlash("cache/download/example.com/g/@v/v1.0.0.mod"))
+	asst.Nil(os.MkdirAll(filepath.Dir(path), constant.DefaultExecFileMode), "test MarkRetractedWithProxy() failed")
+	asst.Nil(os.WriteFile(path, []byte("module example.com/g\n\ngo 1.21\n"), constant.DefaultFileMode), "test MarkRetractedWithProxy() failed")
+	proxyDir, err := filepath.Abs(testFixtureProxyDir)
+	asst.Nil(err, "test MarkRetractedWithProxy() failed")
+
+	node := NewNode(modCacheDir, "example.com/g@v1.0.0")
+	err = MarkRetracted(modCacheDir, nil, []*Node{node})
+	asst.Nil(err, "test MarkRetractedWithProxy() failed")
+	asst.False(node.Retracted, "test MarkRetractedWithProxy() failed")
+
+	proxy := NewProxyClient("file://"+filepath.ToSlash(proxyDir), constant.EmptyString, constant.EmptyString)
+	err = MarkRetracted(modCacheDir, proxy, []*Node{node})
+	asst.Nil(err, "test MarkRetractedWithProxy() failed")
+	asst.True(node.Retracted, "test MarkRetractedWithProxy() failed")
+	asst.Equal("contains a data race", node.RetractRationale, "test MarkRetractedWithProxy() failed")
+}
+
+func TestModController_InitWithIndirect(t *testing.T) {
+	asst := assert.New(t)
+
+	log.SetLevel(log.ErrorLevel)
+
+	c := newTestController(t, ResolverOption(config.ModResolverModFile), IndirectOption(true))
+	err := c.Init()
+	asst.Nil(err, "test InitWithIndirect() failed")
+	asst.Equal(3, len(c.RootNode.ChildNodes), "test InitWithIndirect() failed")
+
+	// the indirect requirement of the main module is included as an indirect edge
+	nodes := c.GetNodes("example.com/d", testModVersion)
+	asst.Equal(1, len(nodes), "test InitWithIndirect() failed")
+	asst.Equal(3, len(nodes[0].ParentNodes), "test InitWithIndirect() failed")
+	asst.True(c.RootNode.IsIndirectChild(nodes[0]), "test InitWithIndirect() failed")
+	asst.Equal(" -> example.com/d@v1.0.0 (indirect)", NewEdge(c.RootNode, nodes[0]).String(), "test InitWithIndirect() failed")
+	aNode := c.GetNodes("example.com/a", testModVersion)[0]
+	asst.False(aNode.IsIndirectChild(nodes[0]), "test InitWithIndirect() failed")
+	asst.Equal("example.com/a@v1.0.0 -> example.com/d@v1.0.0", NewEdge(aNode, nodes[0]).String(), "test InitWithIndirect() failed")
+}
+
+func TestModController_InitWithPrune(t *testing.T) {
+	asst := assert.New(t)
+
+	log.SetLevel(log.ErrorLevel)
+
+	// without pruning, the indirect requirement of the main module is ignored and the whole graph is explored
+	c := newTestControllerWithDir(t, testFixturePruneDir, ResolverOption(config.ModResolverModFile))
+	err := c.Init()
+	asst.Nil(err, "test InitWithPrune() failed")
+	asst.Equal(1, len(c.GetParentChain("example.com/h", testModVersion)), "test InitWithPrune() failed")
+	asst.Equal(0, len(c.GetParentChain("example.com/k", testModVersion)), "test InitWithPrune() failed")
+
+	c = newTestControllerWithDir(t, testFixturePruneDir, ResolverOption(config.ModResolverModFile), PruneOption(true))
+	err = c.Init()
+	asst.Nil(err, "test InitWithPrune() failed")
+	asst.Equal(2, len(c.RootNode.ChildNodes), "test InitWithPrune() failed")
+	// example.com/g is required by a go 1.21 module, so its requirements are pruned
+	gNode := c.GetNodes("example.com/g", testModVersion)[0]
+	asst.True(gNode.Pruned, "test InitWithPrune() failed")
+	asst.Equal("example.com/g@v1.0.0 -> example.com/h@v1.0.0 (pruned)", NewEdge(gNode, gNode.ChildNodes[0]).String(), "test InitWithPrune() failed")
+	asst.Equal(0, len(c.GetParentChain("example.com/h", testModVersion)), "test InitWithPrune() failed")
+	asst.False(c.GetNodes("example.com/h", testModVersion)[0].Selected, "test InitWithPrune() failed")
+	// example.com/i is a go 1.16 module, so all the modules reached through it are loaded
+	asst.False(c.GetNodes("example.com/j", testModVersion)[0].Pruned, "test InitWithPrune() failed")
+	asst.Equal(1, len(c.GetParentChain("example.com/k", testModVersion)), "test InitWithPrune() failed")
+	asst.True(c.GetNodes("example.com/k", testModVersion)[0].Selected, "test InitWithPrune() failed")
+
+	// the list and graph resolvers do not know the go versions of the modules
+	for _, resolverType := range []string{config.ModResolverList, config.ModResolverGraph} {
+		c = newTestControllerWithDir(t, testFixturePruneDir, ResolverOption(resolverType), PruneOption(true))
+		_, err = c.getResolver()
+		asst.NotNil(err, "test InitWithPrune() failed")
+	}
+
+	asst.True((&Node{GoVersion: "1.17"}).IsPrunedGoVersion(), "test InitWithPrune() failed")
+	asst.True((&Node{GoVersion: "1.21rc1"}).IsPrunedGoVersion(), "test InitWithPrune() failed")
+	asst.False((&Node{GoVersion: "1.16"}).IsPrunedGoVersion(), "test InitWithPrune() failed")
+	asst.False((&Node{}).IsPrunedGoVersion(), "test InitWithPrune() failed")
+}
+
+func TestModController_CountParentChains(t *testing.T) {
+	asst := assert.New(t)
+
+	log.SetLevel(log.ErrorLevel)
+
+	c := newTestController(t, ResolverOption(config.ModResolverModFile))
+	err := c.Init()
+	asst.Nil(err, "test CountParentChains() failed")
+
+	graphs := c.GetAncestorGraphs("example.com/d", testModVersion)
+	asst.Equal(1, len(graphs), "test CountParentChains() failed")
+	asst.Equal(4, len(graphs[0].Nodes), "test CountParentChains() failed")
+	asst.Equal(c.RootNode, graphs[0].Nodes[0], "test CountParentChains() failed")
+	asst.Equal(graphs[0].Target, graphs[0].Nodes[3], "test CountParentChains() failed")
+	total, counts := graphs[0].CountParentChains()
+	asst.Equal(int64(2), total.Int64(), "test CountParentChains() failed")
+	asst.Equal(2, len(counts), "test CountParentChains() failed")
+	for _, count := range counts {
+		asst.Equal(c.RootNode, count.Top, "test CountParentChains() failed")
+		asst.Equal(int64(1), count.Count.Int64(), "test CountParentChains() failed")
+	}
+	asst.Equal(len(c.GetParentChain("example.com/d", testModVersion)), int(total.Int64()), "test CountParentChains() failed")
+	// the target node is a top node
+	total, counts = NewAncestorGraph(c.RootNode).CountParentChains()
+	asst.Equal(int64(1), total.Int64(), "test CountParentChains() failed")
+	asst.Equal(0, len(counts), "test CountParentChains() failed")
+
+	c.maxChains = 1
+	chains, truncated := c.getParentChainWithLimit("example.com/d", testModVersion)
+	asst.Equal(1, len(chains), "test CountParentChains() failed")
+	asst.True(truncated, "test CountParentChains() failed")
+	asst.Equal(1, len(c.GetParentChain("example.com/c", testModVersion)), "test CountParentChains() failed")
+	// the chains are not truncated if there are exactly max chains
+	c.maxChains = 2
+	chains, truncated = c.getParentChainWithLimit("example.com/d", testModVersion)
+	asst.Equal(2, len(chains), "test CountParentChains() failed")
+	asst.False(truncated, "test CountParentChains() failed")
+	c.maxChains = 0
+	c.maxDepth = 1
+	asst.Equal(0, len(c.GetParentChain("example.com/d", testModVersion)), "test CountParentChains() failed")
+	c.maxDepth = 2
+	asst.Equal(2, len(c.GetParentChain("example.com/d", testModVersion)), "test CountParentChains() failed")
+
+	c.PrintChainCounts(graphs)
+	c.PrintAncestorGraphs(graphs)
+}
+
+func TestModController_GetShortestParentChains(t *testing.T) {
+	asst := assert.New(t)
+
+	log.SetLevel(log.ErrorLevel)
+
+	c := newTestController(t, ResolverOption(config.ModResolverModFile), IndirectOption(true))
+	err := c.Init()
+	asst.Nil(err, "test GetShortestParentChains() failed")
+
+	chains := c.GetShortestParentChains("example.com/d", testModVersion, 1)
+	asst.Equal(1, len(chains), "test GetShortestParentChains() failed")
+	asst.Equal([]*Node{c.RootNode, c.GetNodes("example.com/d", testModVersion)[0]}, chains[0], "test GetShortestParentChains() failed")
+
+	// the chains are ordered by the length and then by the lexical path
+	chains = c.GetShortestParentChains("example.com/d", testModVersion, 10)
+	asst.Equal(3, len(chains), "test GetShortestParentChains() failed")
+	asst.Equal(2, len(chains[0]), "test GetShortestParentChains() failed")
+	asst.Equal("example.com/a", chains[1][1].Name, "test GetShortestParentChains() failed")
+	asst.Equal("example.com/b", chains[2][1].Name, "test GetShortestParentChains() failed")
+	asst.Equal(len(c.GetParentChain("example.com/d", testModVersion)), len(chains), "test GetShortestParentChains() failed")
+
+	// all the versions of the module are the targets
+	chains = c.GetShortestParentChains("example.com/c", testModVersion, 10)
+	asst.Equal(2, len(chains), "test GetShortestParentChains() failed")
+	asst.Equal("example.com/c@v1.0.0", chains[0][2].FullName, "test GetShortestParentChains() failed")
+	asst.Equal("example.com/c@v1.2.0", chains[1][2].FullName, "test GetShortestParentChains() failed")
+
+	c.maxDepth = 1
+	asst.Equal(1, len(c.GetShortestParentChains("example.com/d", testModVersion, 10)), "test GetShortestParentChains() failed")
+	asst.Equal(0, len(c.GetShortestParentChains("example.com/c", testModVersion, 10)), "test GetShortestParentChains() failed")
+}
+
+func TestModController_GetChildTree(t *testing.T) {
+	asst := assert.New(t)
+
+	log.SetLevel(log.ErrorLevel)
+
+	c := newTestController(t, ResolverOption(config.ModResolverModFile))
+	err := c.Init()
+	asst.Nil(err, "test GetChildTree() failed")
+	lines := c.GetNodes("example.com/b", "v1.1.0")[0].GetChildTree(0)
+	asst.Equal(3, len(lines), "test GetChildTree() failed")
+	asst.Equal("example.com/b@v1.1.0", lines[0], "test GetChildTree() failed")
+	asst.True(strings.HasPrefix(lines[1], "├ example.com/c@v1.2.0"), "test GetChildTree() failed")
+	asst.Equal("└ example.com/d@v1.0.0", lines[2], "test GetChildTree() failed")
+
+	// the repeated subtree is collapsed
+	top := NewNode(testFixtureModDir, constant.EmptyString)
+	y := NewNode(testFixtureModDir, "example.com/y@v1.0.0")
+	z := NewNode(testFixtureModDir, "example.com/z@v1.0.0")
+	w := NewNode(testFixtureModDir, "example.com/w@v1.0.0")
+	top.AddChildNode(y)
+	top.AddChildNode(w)
+	y.AddChildNode(z)
+	w.AddChildNode(y)
+	asst.Equal([]string{
+		testFixtureModDir,
+		"├ example.com/y@v1.0.0",
+		"│ └ example.com/z@v1.0.0",
+		"└ example.com/w@v1.0.0",
+		"  └ example.com/y@v1.0.0 (*)",
+	}, top.GetChildTree(0), "test GetChildTree() failed")
+	asst.Equal([]string{
+		testFixtureModDir,
+		"├ example.com/y@v1.0.0",
+		"└ example.com/w@v1.0.0",
+	}, top.GetChildTree(1), "test GetChildTree() failed")
+}
+
+func TestModController_GetChildTreeWithFilter(t *testing.T) {
+	asst := assert.New(t)
+
+	log.SetLevel(log.ErrorLevel)
+
+	c := newTestController(t, ResolverOption(config.ModResolverModFile), PathPrefixOption("example.com/d"))
+	err := c.Init()
+	asst.Nil(err, "test GetChildTreeWithFilter() failed")
+	lines := c.RootNode.GetChildTreeWithFilter(0, c.getTreeFilter())
+	asst.Equal([]string{
+		c.RootNode.RootPath,
+		"├ example.com/a@v1.0.0",
+		"│ └ example.com/d@v1.0.0",
+		"└ example.com/b@v1.1.0",
+		"  └ example.com/d@v1.0.0",
+	}, lines, "test GetChildTreeWithFilter() failed")
+	asst.Equal(7, len(c.RootNode.GetChildTree(0)), "test GetChildTreeWithFilter() failed")
+
+	// the path prefix matches the leading elements of the module paths
+	c.pathPrefix = "example.com/x,example"
+	asst.Equal(1, len(c.RootNode.GetChildTreeWithFilter(0, c.getTreeFilter())), "test GetChildTreeWithFilter() failed")
+	c.pathPrefix = "example.com/*"
+	asst.Equal(7, len(c.RootNode.GetChildTreeWithFilter(0, c.getTreeFilter())), "test GetChildTreeWithFilter() failed")
+}
+
+func TestModController_GetImportChain(t *testing.T) {
+	asst := assert.New(t)
+
+	log.SetLevel(log.ErrorLevel)
+
+	_, err := exec.LookPath("go")
+	if err != nil {
+		t.Log("go command is not available, skip testing GetImportChain() with the real go command")
+		return
+	}
+
+	t.Setenv("GOPROXY", "off")
+	t.Setenv("GOFLAGS", "-mod=mod")
+	c := newTestControllerWithDir(t, testFixtureWhyDir, ResolverOption(config.ModResolverModFile))
+	err = c.Init()
+	asst.Nil(err, "test GetImportChain() failed")
+
+	// the direct import is shorter than the one through example.com/wa
+	chain, err := c.GetImportChain("example.com/wb", testModVersion)
+	asst.Nil(err, "test GetImportChain() failed")
+	asst.Equal("example.com/why/y", chain.Package, "test GetImportChain() failed")
+	asst.Equal(1, len(chain.Steps), "test GetImportChain() failed")
+	asst.Equal("example.com/wb/pb", chain.Steps[0].Package, "test GetImportChain() failed")
+	asst.Equal(filepath.Join("y", "y.go")+":3", chain.Steps[0].Position, "test GetImportChain() failed")
+	asst.Equal(c.GetNodes("example.com/wb", "v1.0.0")[0], chain.Steps[0].Node, "test GetImportChain() failed")
+
+	chain, err = c.GetImportChain("example.com/wa", "v1.0.0")
+	asst.Nil(err, "test GetImportChain() failed")
+	asst.Equal("example.com/why/internal/x", chain.Package, "test GetImportChain() failed")
+	asst.Equal(1, len(chain.Steps), "test GetImportChain() failed")
+	asst.Equal(filepath.Join("internal", "x", "x.go")+":3", chain.Steps[0].Position, "test GetImportChain() failed")
+	asst.Equal("internal/x/x.go:3 imports example.com/wa/pa (example.com/wa@v1.0.0 => ./wa)", chain.Steps[0].String(), "test GetImportChain() failed")
+
+	chain, err = c.GetImportChain("example.com/wa", "v2.0.0")
+	asst.Nil(err, "test GetImportChain() failed")
+	asst.Nil(chain, "test GetImportChain() failed")
+}
+
+func TestModController_GetImportChainWithExecutor(t *testing.T) {
+	asst := assert.New(t)
+
+	log.SetLevel(log.ErrorLevel)
+
+	yDir, err := filepath.Abs(filepath.Join(testFixtureWhyDir, "y"))
+	asst.Nil(err, "test GetImportChain() failed")
+	output := "go: downloading example.com/wb v1.0.0\n" +
+		`{"ImportPath": "example.com/why", "Imports": ["example.com/why/internal/x", "fmt"], "Module": {"Path": "example.com/why", "Main": true}}` + "\n" +
+		`{"ImportPath": "example.com/why/y", "Dir": "` + yDir + `", "GoFiles": ["y.go"], "Imports": ["example.com/wb/pb"], "Module": {"Path": "example.com/why", "Main": true}}` + "\n" +
+		`{"ImportPath": "example.com/wb/pb", "Module": {"Path": "example.com/wb", "Version": "v1.0.0"}}` + "\n" +
+		`{"ImportPath": "fmt", "Error": {"Err": "package fmt is not in std"}}` + "\n"
+	executor := newTestExecutor()
+	executor.AddOutput(constant.EmptyString, fmt.Sprintf(goListDepsCommandTemplate, allPackagesPattern), output, nil)
+
+	c := newTestControllerWithDir(t, testFixtureWhyDir, ExecutorOption(executor), ResolverOption(config.ModResolverModFile))
+	err = c.Init()
+	asst.Nil(err, "test GetImportChain() failed")
+
+	chain, err := c.GetImportChain("example.com/wb", testModVersion)
+	asst.Nil(err, "test GetImportChain() failed")
+	asst.Equal("example.com/why/y", chain.Package, "test GetImportChain() failed")
+	asst.Equal(1, len(chain.Steps), "test GetImportChain() failed")
+	asst.Equal(filepath.Join("y", "y.go")+":3", chain.Steps[0].Position, "test GetImportChain() failed")
+	asst.Equal(c.GetNodes("example.com/wb", "v1.0.0")[0], chain.Steps[0].Node, "test GetImportChain() failed")
+
+	chain, err = c.GetImportChain("example.com/wa", testModVersion)
+	asst.Nil(err, "test GetImportChain() failed")
+	asst.Nil(chain, "test GetImportChain() failed")
+}
+
+func TestModController_GetModuleVersions(t *testing.T) {
+	asst := assert.New(t)
+
+	log.SetLevel(log.ErrorLevel)
+
+	c := newTestController(t, ResolverOption(config.ModResolverModFile))
+	err := c.Init()
+	asst.Nil(err, "test GetModuleVersions() failed")
+	mv := c.GetModuleVersions("example.com/c")
+	asst.Equal("v1.2.0", mv.Selected, "test GetModuleVersions() failed")
+	asst.Equal(2, len(mv.Versions), "test GetModuleVersions() failed")
+	asst.Equal("v1.0.0 (retracted, 2 minor behind)", mv.Versions[0].String(), "test GetModuleVersions() failed")
+	asst.Equal([]*Requirer{{Module: "example.com/a@v1.0.0"}}, mv.Versions[0].Requirers, "test GetModuleVersions() failed")
+	asst.Equal("v1.2.0 (selected)", mv.Versions[1].String(), "test GetModuleVersions() failed")
+	asst.Equal([]*Requirer{{Module: "example.com/b@v1.1.0"}}, mv.Versions[1].Requirers, "test GetModuleVersions() failed")
+
+	data, err := json.Marshal(mv.Versions[0])
+	asst.Nil(err, "test GetModuleVersions() failed")
+	asst.Equal(`{"version":"v1.0.0","selected":false,"retracted":true,"distance":{"level":"minor","delta":-2},"requirers":[{"module":"example.com/a@v1.0.0","indirect":false}]}`,
+		string(data), "test GetModuleVersions() failed")
+	asst.Equal(0, len(c.GetModuleVersions("example.com/x").Versions), "test GetModuleVersions() failed")
+
+	// the distances between the versions
+	asst.Equal(&SemverDistance{Level: "major", Delta: -1}, NewSemverDistance("v1.9.0", "v2.0.0+incompatible"), "test GetModuleVersions() failed")
+	asst.Equal(&SemverDistance{Level: "patch", Delta: 3}, NewSemverDistance("v1.2.4", "v1.2.1"), "test GetModuleVersions() failed")
+	asst.Equal("prerelease behind", NewSemverDistance("v1.2.0-rc.1", "v1.2.0").String(), "test GetModuleVersions() failed")
+	asst.Equal("none", NewSemverDistance("v1.2.0", "v1.2.0").Level, "test GetModuleVersions() failed")
+}
+
+func TestModController_GetConflicts(t *testing.T) {
+	asst := assert.New(t)
+
+	log.SetLevel(log.ErrorLevel)
+
+	c := newTestController(t, ResolverOption(config.ModResolverModFile))
+	err := c.Init()
+	asst.Nil(err, "test GetConflicts() failed")
+	conflicts := c.GetConflicts()
+	asst.Equal(1, len(conflicts), "test GetConflicts() failed")
+	asst.Equal("example.com/c", conflicts[0].Module, "test GetConflicts() failed")
+	asst.Equal("v1.2.0", conflicts[0].Selected, "test GetConflicts() failed")
+	asst.Equal(&SemverDistance{Level: "minor", Delta: 2}, conflicts[0].Spread, "test GetConflicts() failed")
+	asst.Equal([]string{c.RootNode.RootPath, "example.com/a@v1.0.0", "example.com/c@v1.0.0"}, conflicts[0].LowestChain, "test GetConflicts() failed")
+	asst.Equal([]string{c.RootNode.RootPath, "example.com/b@v1.1.0", "example.com/c@v1.2.0"}, conflicts[0].HighestChain, "test GetConflicts() failed")
+
+	// the conflicts are ranked by the semver spread
+	for _, fullName := range []string{"example.com/x@v1.0.0", "example.com/x@v1.0.3", "example.com/y@v0.1.0", "example.com/y@v2.0.0+incompatible"} {
+		node := NewNode(testFixtureModDir, fullName)
+		c.RootNode.AddChildNode(node)
+		node.AddParentNode(c.RootNode)
+		c.m[fullName] = node
+	}
+	conflicts = c.GetConflicts()
+	asst.Equal(3, len(conflicts), "test GetConflicts() failed")
+	asst.Equal("example.com/y", conflicts[0].Module, "test GetConflicts() failed")
+	asst.Equal("example.com/c", conflicts[1].Module, "test GetConflicts() failed")
+	asst.Equal("example.com/x", conflicts[2].Module, "test GetConflicts() failed")
+	asst.Equal([]string{c.RootNode.RootPath, "example.com/x@v1.0.0"}, conflicts[2].LowestChain, "test GetConflicts() failed")
+}
+
+func TestModController_GetMultipleMajors(t *testing.T) {
+	asst := assert.New(t)
+
+	log.SetLevel(log.ErrorLevel)
+
+	c := newTestController(t, ResolverOption(config.ModResolverModFile), FailOnMultipleMajorsOption(true))
+	err := c.Init()
+	asst.Nil(err, "test GetMultipleMajors() failed")
+	asst.Equal(0, len(c.GetMultipleMajors()), "test GetMultipleMajors() failed")
+	asst.Nil(c.PrintMultipleMajors(), "test GetMultipleMajors() failed")
+
+	// the major versions are grouped by the module path without the major version suffix
+	var nodes []*Node
+	for _, fullName := range []string{"example.com/m@v1.5.0", "example.com/m/v3@v3.0.1", "example.com/m/v2@v2.1.0", "example.com/m/v2@v2.0.0",
+		"gopkg.in/yaml.v2@v2.4.0", "gopkg.in/yaml.v3@v3.0.1"} {
+		node := NewNode(testFixtureModDir, fullName)
+		c.RootNode.AddChildNode(node)
+		node.AddParentNode(c.RootNode)
+		c.m[fullName] = node
+		nodes = append(nodes, node)
+	}
+	MarkSelected(append([]*Node{c.RootNode}, nodes...))
+	multipleMajors := c.GetMultipleMajors()
+	asst.Equal(2, len(multipleMajors), "test GetMultipleMajors() failed")
+	asst.Equal("example.com/m", multipleMajors[0].Module, "test GetMultipleMajors() failed")
+	asst.Equal(3, len(multipleMajors[0].Majors), "test GetMultipleMajors() failed")
+	asst.Equal(&MajorVersion{Path: "example.com/m/v2", Selected: "v2.1.0", Chain: []string{c.RootNode.RootPath, "example.com/m/v2@v2.1.0"}},
+		multipleMajors[0].Majors[1], "test GetMultipleMajors() failed")
+	asst.Equal("example.com/m/v3", multipleMajors[0].Majors[2].Path, "test GetMultipleMajors() failed")
+	asst.Equal("gopkg.in/yaml", multipleMajors[1].Module, "test GetMultipleMajors() failed")
+	asst.Equal(2, len(multipleMajors[1].Majors), "test GetMultipleMajors() failed")
+}
+
+func TestModController_GetNodesWithPattern(t *testing.T) {
+	asst := assert.New(t)
+
+	log.SetLevel(log.ErrorLevel)
+
+	c := newTestController(t, ResolverOption(config.ModResolverModFile))
+	err := c.Init()
+	asst.Nil(err, "test GetNodesWithPattern() failed")
+	asst.Equal(2, len(c.GetNodes("example.com/c", constant.EmptyString)), "test GetNodesWithPattern() failed")
+	asst.Equal(0, len(c.GetNodes("example.com", constant.EmptyString)), "test GetNodesWithPattern() failed")
+
+	// the glob pattern matches the leading elements of the module paths
+	asst.Equal([]string{"example.com/a", "example.com/b", "example.com/c", "example.com/d"}, c.GetModuleNames("example.com/*"), "test GetNodesWithPattern() failed")
+	asst.Equal([]string{"example.com/c", "example.com/d"}, c.GetModuleNames("example.com/[cd]"), "test GetNodesWithPattern() failed")
+	asst.Equal(1, len(c.GetNodes("example.com/?", "v1.2.0")), "test GetNodesWithPattern() failed")
+	err = c.PrintParentChain("example.com/*", constant.EmptyString, false)
+	asst.Nil(err, "test GetNodesWithPattern() failed")
+
+	// the name regex is used if the name is empty
+	c = newTestController(t, ResolverOption(config.ModResolverModFile), NameRegexOption(regexp.MustCompile(`^example\.com/(a|c)$`)))
+	err = c.Init()
+	asst.Nil(err, "test GetNodesWithPattern() failed")
+	asst.Equal([]string{"example.com/a", "example.com/c"}, c.GetModuleNames(constant.EmptyString), "test GetNodesWithPattern() failed")
+	asst.Equal(3, len(c.GetNodes(constant.EmptyString, constant.EmptyString)), "test GetNodesWithPattern() failed")
+	asst.Equal(1, len(c.GetNodes("example.com/b", constant.EmptyString)), "test GetNodesWithPattern() failed")
+	asst.Equal(2, len(c.GetModuleVersions("example.com/c").Versions), "test GetNodesWithPattern() failed")
+}
@@ -0,0 +1,165 @@
+package mod
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/go-util/constant"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+const (
+	downloadCacheDirName = "cache/download"
+	versionDirName       = "@v"
+	modFileExt           = ".mod"
+)
+
+// getDownloadDir returns the directory of the module in the download cache
+func getDownloadDir(rootPath, path string) (string, error) {
+	escapedPath, err := module.EscapePath(path)
+	if err != nil {
+		return constant.EmptyString, errors.Trace(err)
+	}
+
+	return filepath.Join(rootPath, filepath.FromSlash(downloadCacheDirName), escapedPath, versionDirName), nil
+}
+
+// getLocalVersions returns the versions of the module which go.mod files exist in the module cache
+func getLocalVersions(rootPath, path string) ([]string, error) {
+	versions := make(map[string]bool)
+
+	downloadDir, err := getDownloadDir(rootPath, path)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(downloadDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, errors.Trace(err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), modFileExt) {
+			continue
+		}
+		version, err := module.UnescapeVersion(strings.TrimSuffix(entry.Name(), modFileExt))
+		if err == nil && semver.IsValid(version) {
+			versions[version] = true
+		}
+	}
+
+	escapedPath, err := module.EscapePath(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	matches, err := filepath.Glob(filepath.Join(rootPath, escapedPath) + AtString + constant.AsteriskString)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, match := range matches {
+		_, escapedVersion, _ := strings.Cut(filepath.Base(match), AtString)
+		version, err := module.UnescapeVersion(escapedVersion)
+		if err == nil && semver.IsValid(version) {
+			versions[version] = true
+		}
+	}
+
+	var result []string
+	for version := range versions {
+		result = append(result, version)
+	}
+	semver.Sort(result)
+
+	return result, nil
+}
+
+// getLatestVersion returns the latest version of given versions as the go command does
+func getLatestVersion(versions []string) string {
+	var latestPreRelease, latestPseudo string
+	for i := len(versions) - constant.OneInt; i >= constant.ZeroInt; i-- {
+		version := versions[i]
+		switch {
+		case module.IsPseudoVersion(version):
+			if latestPseudo == constant.EmptyString {
+				latestPseudo = version
+			}
+		case semver.Prerelease(version) != constant.EmptyString:
+			if latestPreRelease == constant.EmptyString {
+				latestPreRelease = version
+			}
+		default:
+			return version
+		}
+	}
+	if latestPreRelease != constant.EmptyString {
+		return latestPreRelease
+	}
+
+	return latestPseudo
+}
+
+// readCachedModFile reads the go.mod file of given module version from the module cache
+func readCachedModFile(rootPath, path, version string) (*modfile.File, error) {
+	downloadDir, err := getDownloadDir(rootPath, path)
+	if err != nil {
+		return nil, err
+	}
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	f, err := parseModFile(filepath.Join(downloadDir, escapedVersion+modFileExt), false)
+	if err != nil || f != nil {
+		return f, err
+	}
+
+	node := NewNode(rootPath, path+AtString+version)
+	modDir, err := node.getModDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return parseModFile(filepath.Join(modDir, goModFileName), false)
+}
+
+// writeCachedModFile writes the go.mod file of given module version to the download cache
+func writeCachedModFile(rootPath, path, version string, data []byte) error {
+	downloadDir, err := getDownloadDir(rootPath, path)
+	if err != nil {
+		return err
+	}
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	err = os.MkdirAll(downloadDir, constant.DefaultExecFileMode)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	tmpFile, err := os.CreateTemp(downloadDir, escapedVersion+modFileExt+constant.AsteriskString)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	_, err = tmpFile.Write(data)
+	closeErr := tmpFile.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(tmpFile.Name())
+		return errors.Trace(err)
+	}
+	err = os.Chmod(tmpFile.Name(), constant.DefaultFileMode)
+	if err == nil {
+		err = os.Rename(tmpFile.Name(), filepath.Join(downloadDir, escapedVersion+modFileExt))
+	}
+	if err != nil {
+		_ = os.Remove(tmpFile.Name())
+		return errors.Trace(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,36 @@
+package mod
+
+import (
+	"github.com/romberli/go-util/constant"
+	"golang.org/x/mod/semver"
+)
+
+// MarkSelected marks the versions selected by the minimal version selection
+func MarkSelected(nodes []*Node) {
+	selected := getSelectedVersions(nodes)
+
+	for _, node := range nodes {
+		if node.IsRoot() {
+			node.Selected = true
+			continue
+		}
+		node.SelectedVersion = selected[node.Name]
+		node.Selected = node.Version == node.SelectedVersion
+	}
+}
+
+// getSelectedVersions returns the maximum version of each module path of given nodes
+func getSelectedVersions(nodes []*Node) map[string]string {
+	selected := make(map[string]string)
+	for _, node := range nodes {
+		if node.IsRoot() {
+			continue
+		}
+		version, ok := selected[node.Name]
+		if !ok || semver.Compare(node.Version, version) > constant.ZeroInt {
+			selected[node.Name] = node.Version
+		}
+	}
+
+	return selected
+}
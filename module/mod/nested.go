@@ -0,0 +1,165 @@
+package mod
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/go-util/constant"
+	"github.com/romberli/log"
+)
+
+const (
+	testdataDirName = "testdata"
+)
+
+// findNestedModDirs returns the directories of the nested modules in given module directory
+func findNestedModDirs(dir string) ([]string, error) {
+	var modDirs []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir {
+				return err
+			}
+			log.Warnf("walk directory failed, will ignore it. path: %s, error: %s", path, err.Error())
+			return filepath.SkipDir
+		}
+		if !d.IsDir() {
+			if d.Name() == goModFileName && filepath.Dir(path) != dir {
+				modDirs = append(modDirs, filepath.Dir(path))
+			}
+			return nil
+		}
+		if path != dir && isIgnoredDir(d.Name()) {
+			return filepath.SkipDir
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sort.Strings(modDirs)
+
+	return modDirs, nil
+}
+
+// isIgnoredDir returns if the directory is ignored by the go command when it looks for the packages
+func isIgnoredDir(name string) bool {
+	return name == testdataDirName || name == vendorDirName ||
+		strings.HasPrefix(name, constant.DotString) || strings.HasPrefix(name, constant.UnderBarString)
+}
+
+// getMainRootNodes returns the root nodes except the nested modules
+func (c *Controller) getMainRootNodes() []*Node {
+	var result []*Node
+	for _, rootNode := range c.RootNodes {
+		if !rootNode.Nested {
+			result = append(result, rootNode)
+		}
+	}
+
+	return result
+}
+
+// getNestedRootNodes returns the root nodes of the nested modules
+func (c *Controller) getNestedRootNodes() []*Node {
+	var result []*Node
+	for _, rootNode := range c.RootNodes {
+		if rootNode.Nested {
+			result = append(result, rootNode)
+		}
+	}
+
+	return result
+}
+
+// resolveNestedRootNodes resolves the nested modules with their own directives
+func (c *Controller) resolveNestedRootNodes(resolver Resolver) error {
+	for _, rootNode := range c.getNestedRootNodes() {
+		f, err := parseModFile(filepath.Join(rootNode.RootPath, goModFileName), true)
+		if err != nil {
+			return err
+		}
+		err = rootNode.ResolveConcurrently(resolver, NewDirectives(rootNode.RootPath, f), c.m, c.workers)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// markSelected applies the minimal version selection to the main modules and to each nested module separately
+func (c *Controller) markSelected(workspace bool) {
+	mainRootNodes := c.getMainRootNodes()
+	mainNodes := getReachableNodes(mainRootNodes)
+	if c.prune {
+		// only the nodes in the pruned graph take part in the minimal version selection
+		MarkSelected(MarkPruned(mainRootNodes, mainNodes, workspace))
+	} else {
+		MarkSelected(mainNodes)
+	}
+
+	inMain := make(map[*Node]bool)
+	for _, node := range mainNodes {
+		inMain[node] = true
+	}
+	for _, rootNode := range c.getNestedRootNodes() {
+		nodes := getReachableNodes([]*Node{rootNode})
+		if c.prune {
+			// the pruned flags of the nodes shared with the main modules are kept
+			var nestedNodes []*Node
+			for _, node := range nodes {
+				if !inMain[node] {
+					nestedNodes = append(nestedNodes, node)
+				}
+			}
+			nodes = MarkPruned([]*Node{rootNode}, nestedNodes, false)
+		}
+		rootNode.SelectedVersions = getSelectedVersions(nodes)
+	}
+}
+
+// isSelected returns if the node is selected by the main modules or by any nested module
+func (c *Controller) isSelected(node *Node) bool {
+	if node.Selected || node.IsRoot() {
+		return true
+	}
+	for _, rootNode := range c.getNestedRootNodes() {
+		if rootNode.SelectedVersions[node.Name] == node.Version {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getMainNodes returns the nodes which are reachable from the main modules
+func (c *Controller) getMainNodes() []*Node {
+	return getReachableNodes(c.getMainRootNodes())
+}
+
+// getReachableNodes returns the sorted nodes which are reachable from given root nodes
+func getReachableNodes(rootNodes []*Node) []*Node {
+	visited := make(map[*Node]bool)
+	queue := append([]*Node{}, rootNodes...)
+	var result []*Node
+	for len(queue) > constant.ZeroInt {
+		node := queue[constant.ZeroInt]
+		queue = queue[constant.OneInt:]
+		if visited[node] {
+			continue
+		}
+		visited[node] = true
+		if node.FullName != constant.EmptyString {
+			result = append(result, node)
+		}
+		queue = append(queue, node.ChildNodes...)
+	}
+	sortNodes(result)
+
+	return result
+}
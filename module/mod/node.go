@@ -1,26 +1,32 @@
 package mod
 
 import (
-	"fmt"
+	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/pingcap/errors"
 	"github.com/romberli/go-util/common"
 	"github.com/romberli/go-util/constant"
-	"github.com/romberli/go-util/linux"
 	"github.com/romberli/log"
+	"golang.org/x/mod/modfile"
 	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
 )
 
 const (
 	AtString = "@"
 
-	findModFilesCommandTemplate  = "find %s -type f -name go.mod"
-	getPackagesCommand           = `go list -m -f '{{if not .Indirect}}{{.Path}}@{{.Version}}{{end}}' all | sed '1d'`
-	noSuchFileOrDirectoryMessage = "No such file or directory"
-	missingGoModFile             = "go.mod: no such file or directory"
-	missingGoSumFile             = "missing go.sum entry for go.mod file"
+	replaceArrowString = " => "
+	edgeArrowString    = " -> "
+	retractedString    = "retracted"
+	supersededByString = "superseded by "
+
+	getPackagesCommand         = `go list -m -f '{{if not .Indirect}}{{.Path}}@{{.Version}}{{end}}' all | sed '1d'`
+	getPackagesIndirectCommand = `go list -m -f '{{.Path}}@{{.Version}}{{if .Indirect}} // indirect{{end}}' all | sed '1d'`
+	missingGoModFile           = "go.mod: no such file or directory"
+	missingGoSumFile           = "missing go.sum entry for go.mod file"
 
 	goModDownloadCommand = "go mod download"
 )
@@ -32,6 +38,29 @@ type Node struct {
 	Version  string
 	Finished bool
 
+	// ReplaceName, ReplaceVersion and ReplaceDir are the target of the replace directive
+	ReplaceName    string
+	ReplaceVersion string
+	ReplaceDir     string
+	// Retracted is true if the version is retracted by the latest version of the module
+	Retracted        bool
+	RetractRationale string
+	// Selected is true if the version is selected by the minimal version selection
+	Selected        bool
+	SelectedVersion string
+	// SelectedVersions is the selected version of each module path in the graph of a nested module
+	SelectedVersions map[string]string
+
+	// IndirectChildNames contains the full names of the child nodes which are required indirectly
+	IndirectChildNames map[string]bool
+	// GoVersion is the go version of the module, Pruned is true if its requirements are pruned out
+	GoVersion string
+	Pruned    bool
+	// Skipped is true if the requirements of the module are unknown
+	Skipped bool
+	// Nested is true if the node is a nested module of the main module
+	Nested bool
+
 	ParentNodes []*Node
 	ChildNodes  []*Node
 }
@@ -60,7 +89,46 @@ func NewNode(rootPath, fullName string) *Node {
 }
 
 func (n *Node) String() string {
-	return n.FullName
+	result := n.FullName
+	if n.IsReplaced() {
+		replacement := n.ReplaceName
+		if n.ReplaceVersion != constant.EmptyString {
+			replacement += constant.SpaceString + n.ReplaceVersion
+		}
+		result += replaceArrowString + replacement
+	}
+	if n.Retracted {
+		retracted := retractedString
+		if n.RetractRationale != constant.EmptyString {
+			retracted += constant.ColonString + constant.SpaceString + n.RetractRationale
+		}
+		result += constant.SpaceString + constant.LeftParenthesisString + retracted + constant.RightParenthesisString
+	}
+	if n.IsSuperseded() {
+		result += constant.SpaceString + constant.LeftParenthesisString + supersededByString + n.SelectedVersion + constant.RightParenthesisString
+	}
+
+	return result
+}
+
+// IsRoot returns if the node is a main module
+func (n *Node) IsRoot() bool {
+	return n.Version == constant.EmptyString
+}
+
+// IsSuperseded returns if the version is not selected by the minimal version selection
+func (n *Node) IsSuperseded() bool {
+	return !n.Selected && n.SelectedVersion != constant.EmptyString
+}
+
+// IsReplaced returns if the node is replaced by the replace directive of the main module
+func (n *Node) IsReplaced() bool {
+	return n.ReplaceName != constant.EmptyString
+}
+
+// IsIndirectChild returns if the child node is required by the node indirectly
+func (n *Node) IsIndirectChild(childNode *Node) bool {
+	return n.IndirectChildNames[childNode.FullName]
 }
 
 func (n *Node) AddParentNode(parentNode *Node) {
@@ -71,18 +139,37 @@ func (n *Node) AddChildNode(childNode *Node) {
 	n.ChildNodes = append(n.ChildNodes, childNode)
 }
 
+// GetParentChain returns all the chains from the root nodes to the node
 func (n *Node) GetParentChain() [][]*Node {
-	var result [][]*Node
-
-	n.getParentChain(&result, []*Node{})
+	result, _ := n.GetParentChainWithLimit(constant.ZeroInt, constant.ZeroInt)
 
 	return result
 }
 
-func (n *Node) getParentChain(result *[][]*Node, current []*Node) {
+// GetParentChainWithLimit returns the limited chains from the root nodes to the node and if any of them are cut off
+func (n *Node) GetParentChainWithLimit(maxChains, maxDepth int) ([][]*Node, bool) {
+	var (
+		result    [][]*Node
+		truncated bool
+	)
+
+	n.getParentChain(&result, &truncated, []*Node{}, make(map[*Node]bool), maxChains, maxDepth)
+
+	return result, truncated
+}
+
+func (n *Node) getParentChain(result *[][]*Node, truncated *bool, current []*Node, onPath map[*Node]bool, maxChains, maxDepth int) {
+	if *truncated {
+		return
+	}
 	current = append(current, n)
 
 	if len(n.ParentNodes) == constant.ZeroInt {
+		if maxChains > constant.ZeroInt && len(*result) >= maxChains {
+			// one more chain is found, so the chains are truncated
+			*truncated = true
+			return
+		}
 		var tmp []*Node
 		for i := len(current) - constant.OneInt; i >= constant.ZeroInt; i-- {
 			tmp = append(tmp, current[i])
@@ -90,119 +177,310 @@ func (n *Node) getParentChain(result *[][]*Node, current []*Node) {
 		*result = append(*result, tmp)
 		return
 	}
+	if maxDepth > constant.ZeroInt && len(current) > maxDepth {
+		// the chain through any parent has more edges than the max depth
+		return
+	}
 
+	onPath[n] = true
 	for _, parentNode := range n.ParentNodes {
-		parentNode.getParentChain(result, current)
+		if onPath[parentNode] {
+			// cycle, the chain through this parent never reaches a root node
+			continue
+		}
+		if parentNode.Pruned {
+			continue
+		}
+		parentNode.getParentChain(result, truncated, current, onPath, maxChains, maxDepth)
 	}
+	delete(onPath, n)
+}
+
+// Resolve resolves the requirements of the node recursively with given resolver
+func (n *Node) Resolve(resolver Resolver, m map[string]*Node) error {
+	return n.ResolveConcurrently(resolver, nil, m, constant.OneInt)
+}
+
+// resolveResult is the result of resolving the direct requirements of a node
+type resolveResult struct {
+	node     *Node
+	packages []string
+	err      error
+}
+
+// ResolveConcurrently resolves the requirements of the node with given number of workers
+func (n *Node) ResolveConcurrently(resolver Resolver, directives *Directives, m map[string]*Node, workers int) error {
+	return ResolveConcurrently([]*Node{n}, resolver, directives, m, workers)
 }
 
-func (n *Node) Resolve(m map[string]*Node) error {
-	if n.FullName != constant.EmptyString {
-		m[n.FullName] = n
+// ResolveConcurrently resolves the requirements of given nodes with given number of workers
+func ResolveConcurrently(nodes []*Node, resolver Resolver, directives *Directives, m map[string]*Node, workers int) error {
+	if workers < constant.OneInt {
+		workers = constant.OneInt
+	}
+
+	jobs := make(chan *Node)
+	results := make(chan *resolveResult)
+	for i := constant.ZeroInt; i < workers; i++ {
+		go func() {
+			for node := range jobs {
+				packages, err := resolver.GetChildPackages(node)
+				results <- &resolveResult{node: node, packages: packages, err: err}
+			}
+		}()
+	}
+	defer close(jobs)
+
+	var (
+		queue   []*Node
+		pending int
+		err     error
+	)
+	resolved := make(map[*Node]bool)
+	for _, node := range nodes {
+		if node.FullName != constant.EmptyString {
+			m[node.FullName] = node
+		}
+		if !node.Finished {
+			queue = append(queue, node)
+		}
 	}
-	if n.Finished {
-		return nil
+
+	for len(queue) > constant.ZeroInt || pending > constant.ZeroInt {
+		var (
+			jobChan chan *Node
+			next    *Node
+		)
+		if len(queue) > constant.ZeroInt && err == nil {
+			jobChan = jobs
+			next = queue[constant.ZeroInt]
+		}
+
+		select {
+		case jobChan <- next:
+			queue = queue[constant.OneInt:]
+			pending++
+		case result := <-results:
+			pending--
+			if result.err != nil {
+				if err == nil {
+					err = result.err
+				}
+				queue = nil
+				continue
+			}
+			if err != nil {
+				continue
+			}
+			queue = append(queue, result.node.link(result.packages, directives, m)...)
+			result.node.Finished = true
+			resolved[result.node] = true
+		}
 	}
-	packages, err := n.getChildPackages()
 	if err != nil {
 		return err
 	}
 
+	for node := range resolved {
+		for _, childNode := range node.ChildNodes {
+			childNode.sortParentNodes()
+		}
+	}
+
+	return nil
+}
+
+// link links the child nodes of given packages with the node and returns the unresolved ones
+func (n *Node) link(packages []string, directives *Directives, m map[string]*Node) []*Node {
+	rootPath := n.RootPath
+	if n.IsRoot() {
+		// root node
+		rootPath = defaultPackageRootPath
+	}
+
+	var unresolved []*Node
 	for _, pkg := range packages {
-		rootPath := n.RootPath
-		if n.FullName == constant.EmptyString {
-			// root node
-			rootPath = defaultPackageRootPath
+		pkg, indirect := strings.CutSuffix(pkg, indirectSuffix)
+		name, version, _ := strings.Cut(pkg, AtString)
+		if directives.IsWorkspaceModule(name) {
+			// the workspace module is interned with its module path
+			pkg = name
+		} else if directives.IsExcluded(name, version) {
+			log.Debugf("module version is excluded by the main module, will ignore it. module: %s", pkg)
+			continue
 		}
 		childNode, ok := m[pkg]
 		if !ok {
 			childNode = NewNode(rootPath, pkg)
+			directives.Replace(childNode)
+			m[pkg] = childNode
+			unresolved = append(unresolved, childNode)
+		}
+		if hasChildNode(n, childNode) {
+			// the module is required more than once, the direct requirement takes precedence
+			if !indirect {
+				delete(n.IndirectChildNames, childNode.FullName)
+			}
+			continue
+		}
+		if indirect {
+			if n.IndirectChildNames == nil {
+				n.IndirectChildNames = make(map[string]bool)
+			}
+			n.IndirectChildNames[childNode.FullName] = true
 		}
 		childNode.AddParentNode(n)
 		n.AddChildNode(childNode)
-
-		err = childNode.Resolve(m)
-		if err != nil {
-			return err
-		}
 	}
 
-	n.Finished = true
+	return unresolved
+}
 
-	return nil
+// sortParentNodes sorts the parent nodes by the module path and the semantic version
+func (n *Node) sortParentNodes() {
+	sortNodes(n.ParentNodes)
 }
 
-func (n *Node) getChildPackages() ([]string, error) {
-	modDirs, err := n.getModDirs()
+// getChildPackages executes go list command in the module directory to get the requirements
+func (n *Node) getChildPackages(executor Executor, indirect bool) ([]string, error) {
+	modDir, err := n.getModDir()
 	if err != nil {
 		return nil, err
 	}
-
-	var packages []string
-
-	for _, dir := range modDirs {
-		output, err := linux.ExecuteCommand(getPackagesCommand, linux.WorkDirOption(dir), linux.UseSHCOption())
-		if err != nil {
-			return nil, err
+	_, err = os.Stat(filepath.Join(modDir, goModFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			log.Warnf("go.mod file does not exist, maybe because the package is only dependent by certain build conditions, will ignore it. path: %s", modDir)
+			n.Skipped = true
+			return nil, nil
 		}
+		return nil, errors.Trace(err)
+	}
 
-		packagesList := strings.Split(strings.TrimSpace(output), constant.CRLFString)
-		for _, pkg := range packagesList {
-			if pkg != constant.EmptyString && !common.ElementInSlice(packages, pkg) {
-				if strings.Contains(pkg, missingGoModFile) {
-					log.Warnf("package can not find appropriate go.mod, will ignore it. packageName: %s", pkg)
-					continue
-				}
-				if strings.Contains(pkg, missingGoSumFile) {
-					log.Warnf("package is missing go.sum file, will ignore it. packageName: %s", pkg)
-					continue
-				}
-				if strings.Contains(pkg, goModDownloadCommand) {
-					log.Warnf("packag is not downloaded, will ignore it. packageName: %s", pkg)
-					continue
-				}
-				packages = append(packages, pkg)
+	command := getPackagesCommand
+	if indirect {
+		command = getPackagesIndirectCommand
+	}
+	output, err := executor.ExecuteCommand(modDir, command, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var packages []string
+	for _, pkg := range strings.Split(strings.TrimSpace(output), constant.CRLFString) {
+		if pkg != constant.EmptyString && !common.ElementInSlice(packages, pkg) {
+			if strings.Contains(pkg, missingGoModFile) {
+				log.Warnf("package can not find appropriate go.mod, will ignore it. packageName: %s", pkg)
+				n.Skipped = true
+				continue
+			}
+			if strings.Contains(pkg, missingGoSumFile) {
+				log.Warnf("package is missing go.sum file, will ignore it. packageName: %s", pkg)
+				n.Skipped = true
+				continue
 			}
+			if strings.Contains(pkg, goModDownloadCommand) {
+				log.Warnf("packag is not downloaded, will ignore it. packageName: %s", pkg)
+				n.Skipped = true
+				continue
+			}
+			packages = append(packages, pkg)
 		}
 	}
 
 	return packages, nil
 }
 
-func (n *Node) getModDirs() ([]string, error) {
-	var err error
-	path := n.RootPath
-	if n.FullName != constant.EmptyString {
-		path, err = module.EscapePath(strings.TrimSuffix(n.Name, constant.SlashString))
-		if err != nil {
-			return nil, errors.Trace(err)
-		}
-		path = filepath.Join(n.RootPath, path) + AtString + n.Version
+// readModFile reads the go.mod file of the module, it returns nil if the go.mod file does not exist
+func (n *Node) readModFile(proxy *ProxyClient) (*modfile.File, error) {
+	if n.IsRoot() {
+		return parseModFile(filepath.Join(n.RootPath, goModFileName), true)
+	}
+	if n.ReplaceDir != constant.EmptyString {
+		return parseModFile(filepath.Join(n.ReplaceDir, goModFileName), false)
+	}
+
+	name, version := n.Name, n.Version
+	if n.IsReplaced() {
+		name, version = n.ReplaceName, n.ReplaceVersion
 	}
-	cmd := fmt.Sprintf(findModFilesCommandTemplate, path)
-	output, err := linux.ExecuteCommand(cmd)
+	f, err := readCachedModFile(n.RootPath, name, version)
+	if err != nil || f != nil || proxy == nil {
+		return f, err
+	}
+
+	data, err := proxy.GoMod(name, version)
+	if err != nil {
+		log.Warnf("fetch go.mod file from the proxies failed, will skip it. module: %s@%s, error: %s", name, version, err.Error())
+		return nil, nil
+	}
+	if data == nil {
+		return nil, nil
+	}
+	f, err = modfile.ParseLax(name+AtString+version+modFileExt, data, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	verified, err := proxy.VerifyGoMod(name, version, data)
 	if err != nil {
-		if strings.Contains(output, noSuchFileOrDirectoryMessage) {
-			log.Warnf("path does not exist, maybe because the package is only dependent by certain build conditions, will ignore it. path: %s", path)
-			return nil, nil
-		}
 		return nil, err
 	}
+	if !verified {
+		log.Debugf("fetched go.mod file is not in go.sum, will not write it to the module cache. module: %s@%s", name, version)
+		return f, nil
+	}
+	err = writeCachedModFile(n.RootPath, name, version, data)
+	if err != nil {
+		log.Warnf("write go.mod file to the module cache failed, will ignore it. module: %s@%s, error: %s", name, version, err.Error())
+	}
 
-	output = strings.TrimSpace(output)
-	if output == constant.EmptyString {
-		return nil, nil
+	return f, nil
+}
+
+// getModDir returns the directory of the module
+func (n *Node) getModDir() (string, error) {
+	if n.IsRoot() {
+		return n.RootPath, nil
+	}
+	if n.ReplaceDir != constant.EmptyString {
+		return n.ReplaceDir, nil
 	}
 
-	var modDirs []string
+	name, version := n.Name, n.Version
+	if n.IsReplaced() {
+		name, version = n.ReplaceName, n.ReplaceVersion
+	}
 
-	outputList := strings.Split(output, constant.CRLFString)
-	for _, line := range outputList {
-		dir := filepath.Dir(line)
-		modDirs = append(modDirs, dir)
+	path, err := module.EscapePath(strings.TrimSuffix(name, constant.SlashString))
+	if err != nil {
+		return constant.EmptyString, errors.Trace(err)
+	}
+	version, err = module.EscapeVersion(version)
+	if err != nil {
+		return constant.EmptyString, errors.Trace(err)
+	}
+
+	return filepath.Join(n.RootPath, path) + AtString + version, nil
+}
+
+// sortNodes sorts the nodes by the module path and the semantic version
+func sortNodes(nodes []*Node) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		return lessNode(nodes[i], nodes[j])
+	})
+}
+
+// lessNode returns if the node a should be sorted before the node b
+func lessNode(a, b *Node) bool {
+	if a.Name != b.Name {
+		return a.Name < b.Name
+	}
+	if c := semver.Compare(a.Version, b.Version); c != constant.ZeroInt {
+		return c < constant.ZeroInt
 	}
 
-	return modDirs, nil
+	return a.Version < b.Version
 }
 
 type NodeList []*Node
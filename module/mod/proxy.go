@@ -0,0 +1,257 @@
+package mod
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/go-util/constant"
+	"github.com/romberli/log"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+	"golang.org/x/mod/sumdb/dirhash"
+)
+
+const (
+	getProxyEnvCommand = "go env GOPROXY GONOPROXY GOPRIVATE"
+
+	proxyDirect        = "direct"
+	proxyOff           = "off"
+	proxyFileScheme    = "file"
+	proxyListFile      = "list"
+	proxyInfoFileExt   = ".info"
+	proxyZipFileExt    = ".zip"
+	proxyListSeparator = ","
+	proxyPipeSeparator = "|"
+	goSumModSuffix     = "/go.mod"
+
+	defaultProxyTimeout = 30 * time.Second
+)
+
+// ModuleInfo is the metadata of a module version which is returned by the .info endpoint of the proxy
+type ModuleInfo struct {
+	Version string
+	Time    time.Time
+}
+
+// proxyEntry is an element of GOPROXY
+type proxyEntry struct {
+	url             string
+	fallBackOnError bool
+}
+
+// ProxyClient fetches the module files with the GOPROXY protocol
+type ProxyClient struct {
+	proxies []*proxyEntry
+	noProxy string
+	client  *http.Client
+	sums    map[module.Version]string
+}
+
+// NewProxyClient returns a new *ProxyClient with the values of GOPROXY, GONOPROXY and GOPRIVATE
+func NewProxyClient(goProxy, goNoProxy, goPrivate string) *ProxyClient {
+	if goNoProxy == constant.EmptyString {
+		goNoProxy = goPrivate
+	}
+	pc := &ProxyClient{
+		noProxy: goNoProxy,
+		client:  &http.Client{Timeout: defaultProxyTimeout},
+		sums:    make(map[module.Version]string),
+	}
+
+	for goProxy != constant.EmptyString {
+		var (
+			element         string
+			fallBackOnError bool
+		)
+		i := strings.IndexAny(goProxy, proxyListSeparator+proxyPipeSeparator)
+		if i < constant.ZeroInt {
+			element, goProxy = goProxy, constant.EmptyString
+		} else {
+			element, fallBackOnError, goProxy = goProxy[:i], goProxy[i:i+constant.OneInt] == proxyPipeSeparator, goProxy[i+constant.OneInt:]
+		}
+		element = strings.TrimSpace(element)
+		if element == constant.EmptyString {
+			continue
+		}
+		pc.proxies = append(pc.proxies, &proxyEntry{url: strings.TrimSuffix(element, constant.SlashString), fallBackOnError: fallBackOnError})
+	}
+
+	return pc
+}
+
+// LoadGoSum loads the hashes of the go.mod files in the go.sum file
+func (pc *ProxyClient) LoadGoSum(file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return errors.Trace(err)
+	}
+
+	for _, line := range strings.Split(string(data), constant.CRLFString) {
+		fields := strings.Fields(line)
+		if len(fields) != constant.ThreeInt || !strings.HasSuffix(fields[constant.OneInt], goSumModSuffix) {
+			continue
+		}
+		mv := module.Version{Path: fields[constant.ZeroInt], Version: strings.TrimSuffix(fields[constant.OneInt], goSumModSuffix)}
+		pc.sums[mv] = fields[constant.TwoInt]
+	}
+
+	return nil
+}
+
+// VerifyGoMod verifies the go.mod file of the module version with the loaded go.sum files
+func (pc *ProxyClient) VerifyGoMod(path, version string, data []byte) (bool, error) {
+	sum, ok := pc.sums[module.Version{Path: path, Version: version}]
+	if !ok {
+		return false, nil
+	}
+
+	hash, err := dirhash.Hash1([]string{goModFileName}, func(string) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	})
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if hash != sum {
+		return false, errors.Errorf("ProxyClient.VerifyGoMod(): checksum mismatch of go.mod file. module: %s@%s, downloaded: %s, go.sum: %s", path, version, hash, sum)
+	}
+
+	return true, nil
+}
+
+// List returns the known versions of the module which are sorted by the semantic version
+func (pc *ProxyClient) List(path string) ([]string, error) {
+	data, err := pc.fetch(path, proxyListFile)
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	var versions []string
+	for _, line := range strings.Split(string(data), constant.CRLFString) {
+		version := strings.TrimSpace(line)
+		if semver.IsValid(version) {
+			versions = append(versions, version)
+		}
+	}
+	semver.Sort(versions)
+
+	return versions, nil
+}
+
+// Info returns the metadata of the module version
+func (pc *ProxyClient) Info(path, version string) (*ModuleInfo, error) {
+	data, err := pc.fetchVersionFile(path, version, proxyInfoFileExt)
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	info := &ModuleInfo{}
+	err = json.Unmarshal(data, info)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return info, nil
+}
+
+// GoMod returns the content of the go.mod file of the module version
+func (pc *ProxyClient) GoMod(path, version string) ([]byte, error) {
+	return pc.fetchVersionFile(path, version, modFileExt)
+}
+
+// Zip returns the content of the zip file of the module version
+func (pc *ProxyClient) Zip(path, version string) ([]byte, error) {
+	return pc.fetchVersionFile(path, version, proxyZipFileExt)
+}
+
+// fetchVersionFile fetches the file of the module version with given file extension
+func (pc *ProxyClient) fetchVersionFile(path, version, ext string) ([]byte, error) {
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return pc.fetch(path, escapedVersion+ext)
+}
+
+// fetch fetches the file of the module from the proxies in order
+func (pc *ProxyClient) fetch(path, file string) ([]byte, error) {
+	if module.MatchPrefixPatterns(pc.noProxy, path) {
+		log.Debugf("module matches GONOPROXY, will not fetch it from the proxies. module: %s", path)
+		return nil, nil
+	}
+	escapedPath, err := module.EscapePath(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	filePath := escapedPath + constant.SlashString + versionDirName + constant.SlashString + file
+
+	for _, proxy := range pc.proxies {
+		switch proxy.url {
+		case proxyOff:
+			return nil, errors.Errorf("ProxyClient.fetch(): module lookup is disabled by GOPROXY=off. module: %s", path)
+		case proxyDirect:
+			log.Debugf("fetching modules directly from the version control systems is not supported, will ignore it. module: %s", path)
+			continue
+		}
+
+		data, err := pc.fetchFromProxy(proxy.url, filePath)
+		if err != nil {
+			if proxy.fallBackOnError {
+				log.Warnf("fetch from proxy failed, will try the next proxy. proxy: %s, file: %s, error: %s", proxy.url, filePath, err.Error())
+				continue
+			}
+			return nil, err
+		}
+		if data != nil {
+			return data, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// fetchFromProxy fetches the file from the proxy, it returns nil if the file is not found
+func (pc *ProxyClient) fetchFromProxy(proxyURL, filePath string) ([]byte, error) {
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	if u.Scheme == proxyFileScheme {
+		data, err := os.ReadFile(filepath.Join(filepath.FromSlash(u.Path), filepath.FromSlash(filePath)))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, nil
+			}
+			return nil, errors.Trace(err)
+		}
+		return data, nil
+	}
+
+	resp, err := pc.client.Get(proxyURL + constant.SlashString + filePath)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		data, err := io.ReadAll(resp.Body)
+		return data, errors.Trace(err)
+	case http.StatusNotFound, http.StatusGone:
+		return nil, nil
+	default:
+		return nil, errors.Errorf("ProxyClient.fetchFromProxy(): unexpected status code. proxy: %s, file: %s, status: %s", proxyURL, filePath, resp.Status)
+	}
+}
@@ -0,0 +1,92 @@
+package mod
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/romberli/go-util/constant"
+)
+
+const (
+	prunedString = "pruned"
+
+	// prunedMajorGoVersion and prunedMinorGoVersion are the first go version which prunes the module graph
+	prunedMajorGoVersion = 1
+	prunedMinorGoVersion = 17
+)
+
+// MarkPruned marks the nodes which requirements are pruned out and returns the nodes in the pruned graph
+func MarkPruned(rootNodes, nodes []*Node, workspace bool) []*Node {
+	loaded := make(map[*Node]bool)
+	loadedUnpruned := make(map[*Node]bool)
+
+	var load func(node *Node, unpruned bool)
+	load = func(node *Node, unpruned bool) {
+		if unpruned {
+			if loadedUnpruned[node] {
+				return
+			}
+			loadedUnpruned[node] = true
+		} else if loaded[node] {
+			return
+		}
+		loaded[node] = true
+
+		if !unpruned && node.IsPrunedGoVersion() {
+			// the requirements of the children are pruned
+			return
+		}
+		for _, childNode := range node.ChildNodes {
+			load(childNode, true)
+		}
+	}
+
+	for _, rootNode := range rootNodes {
+		loaded[rootNode] = true
+		unpruned := !workspace && !rootNode.IsPrunedGoVersion()
+		for _, childNode := range rootNode.ChildNodes {
+			load(childNode, unpruned)
+		}
+	}
+
+	inGraph := make(map[*Node]bool)
+	var result []*Node
+	for node := range loaded {
+		for _, n := range append([]*Node{node}, node.ChildNodes...) {
+			if !inGraph[n] && n.FullName != constant.EmptyString {
+				inGraph[n] = true
+				result = append(result, n)
+			}
+		}
+	}
+	sortNodes(result)
+
+	for _, node := range nodes {
+		node.Pruned = !loaded[node]
+	}
+
+	return result
+}
+
+// IsPrunedGoVersion returns if the go version of the module is go 1.17 or later
+func (n *Node) IsPrunedGoVersion() bool {
+	major, rest, _ := strings.Cut(n.GoVersion, constant.DotString)
+	minor, _, _ := strings.Cut(rest, constant.DotString)
+	// the pre-release versions such as 1.21rc1
+	i := strings.IndexFunc(minor, func(r rune) bool { return !unicode.IsDigit(r) })
+	if i >= constant.ZeroInt {
+		minor = minor[:i]
+	}
+
+	majorNum, err := strconv.Atoi(major)
+	if err != nil {
+		return false
+	}
+	minorNum, err := strconv.Atoi(minor)
+	if err != nil {
+		return false
+	}
+
+	return majorNum > prunedMajorGoVersion || (majorNum == prunedMajorGoVersion && minorNum >= prunedMinorGoVersion)
+}
@@ -0,0 +1,169 @@
+package mod
+
+import (
+	"os"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/go-util/common"
+	"github.com/romberli/go-util/constant"
+	"github.com/romberli/log"
+	"golang.org/x/mod/modfile"
+)
+
+const (
+	goModFileName = "go.mod"
+
+	indirectSuffix = " // indirect"
+
+	goDirectivePath        = "go"
+	toolchainDirectivePath = "toolchain"
+)
+
+// Resolver resolves the direct requirements of a node
+type Resolver interface {
+	// GetChildPackages returns the direct requirements of the node, each element is formatted as path@version
+	GetChildPackages(node *Node) ([]string, error)
+}
+
+// ListResolver executes go list command in every module directory to get the requirements
+type ListResolver struct {
+	executor Executor
+	indirect bool
+}
+
+// NewListResolver returns a new *ListResolver
+func NewListResolver(executor Executor, indirect bool) *ListResolver {
+	return &ListResolver{executor: executor, indirect: indirect}
+}
+
+// GetChildPackages returns the direct requirements of the node
+func (lr *ListResolver) GetChildPackages(node *Node) ([]string, error) {
+	return node.getChildPackages(lr.executor, lr.indirect)
+}
+
+// ModFileResolver parses the go.mod file of the module to get the requirements
+type ModFileResolver struct {
+	proxy    *ProxyClient
+	indirect bool
+}
+
+// NewModFileResolver returns a new *ModFileResolver, proxy could be nil
+func NewModFileResolver(proxy *ProxyClient, indirect bool) *ModFileResolver {
+	return &ModFileResolver{proxy: proxy, indirect: indirect}
+}
+
+// GetChildPackages returns the direct requirements of the node
+func (mr *ModFileResolver) GetChildPackages(node *Node) ([]string, error) {
+	f, err := node.readModFile(mr.proxy)
+	if err != nil {
+		return nil, err
+	}
+	if f == nil {
+		log.Warnf("go.mod file does not exist, maybe because the package is only dependent by certain build conditions, will ignore it. module: %s", node.FullName)
+		node.Skipped = true
+		return nil, nil
+	}
+	setGoVersion(node, f)
+
+	return getRequirePackages(f, mr.indirect), nil
+}
+
+// setGoVersion sets the go version of the node with the go directive of the go.mod file
+func setGoVersion(node *Node, f *modfile.File) {
+	if f.Go != nil {
+		node.GoVersion = f.Go.Version
+	}
+}
+
+// getRequirePackages returns the requirements in the go.mod file
+func getRequirePackages(f *modfile.File, indirect bool) []string {
+	var packages []string
+	for _, require := range f.Require {
+		pkg := require.Mod.Path + AtString + require.Mod.Version
+		if require.Indirect {
+			if !indirect {
+				continue
+			}
+			pkg += indirectSuffix
+		}
+		if !common.ElementInSlice(packages, pkg) {
+			packages = append(packages, pkg)
+		}
+	}
+
+	return packages
+}
+
+// GraphResolver builds the whole graph with the output of go mod graph command
+type GraphResolver struct {
+	rootPackages []string
+	edges        map[string][]string
+}
+
+// NewGraphResolver returns a new *GraphResolver with the output of go mod graph command
+func NewGraphResolver(graph string) (*GraphResolver, error) {
+	gr := &GraphResolver{
+		edges: make(map[string][]string),
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(graph), constant.CRLFString) {
+		line = strings.TrimSpace(line)
+		if line == constant.EmptyString {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != constant.TwoInt {
+			return nil, errors.Errorf("NewGraphResolver(): line format is not valid. line: %s", line)
+		}
+		from, to := fields[constant.ZeroInt], fields[constant.OneInt]
+		toPath, _, _ := strings.Cut(to, AtString)
+		if toPath == goDirectivePath || toPath == toolchainDirectivePath {
+			// go and toolchain directives are reported as edges since go 1.21
+			continue
+		}
+		if !strings.Contains(from, AtString) {
+			// the main module, there are more than one main modules in workspace mode
+			if !common.ElementInSlice(gr.rootPackages, to) {
+				gr.rootPackages = append(gr.rootPackages, to)
+			}
+		}
+		if !common.ElementInSlice(gr.edges[from], to) {
+			gr.edges[from] = append(gr.edges[from], to)
+		}
+	}
+
+	return gr, nil
+}
+
+// GetChildPackages returns the direct requirements of the node
+func (gr *GraphResolver) GetChildPackages(node *Node) ([]string, error) {
+	if node.FullName == constant.EmptyString {
+		return gr.rootPackages, nil
+	}
+	packages, ok := gr.edges[node.FullName]
+	if !ok && node.IsRoot() {
+		return NewModFileResolver(nil, false).GetChildPackages(node)
+	}
+
+	return packages, nil
+}
+
+// parseModFile reads and parses the go.mod file, it returns nil if the file does not exist
+func parseModFile(path string, strict bool) (*modfile.File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Trace(err)
+	}
+
+	if strict {
+		f, err := modfile.Parse(path, data, nil)
+		return f, errors.Trace(err)
+	}
+
+	f, err := modfile.ParseLax(path, data, nil)
+	return f, errors.Trace(err)
+}
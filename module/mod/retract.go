@@ -0,0 +1,88 @@
+package mod
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/romberli/go-util/constant"
+	"github.com/romberli/log"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+)
+
+// MarkRetracted marks the nodes which versions are retracted by the latest versions of their modules
+func MarkRetracted(rootPath string, proxy *ProxyClient, nodes []*Node) error {
+	retracts := make(map[string][]*modfile.Retract)
+
+	for _, node := range nodes {
+		node.Retracted = false
+		node.RetractRationale = constant.EmptyString
+		if node.IsRoot() || node.ReplaceDir != constant.EmptyString {
+			continue
+		}
+		rs, ok := retracts[node.Name]
+		if !ok {
+			var err error
+			rs, err = getLatestRetracts(rootPath, proxy, node.Name)
+			if err != nil {
+				return err
+			}
+			retracts[node.Name] = rs
+		}
+
+		for _, r := range rs {
+			if semver.Compare(r.Low, node.Version) <= constant.ZeroInt && semver.Compare(node.Version, r.High) <= constant.ZeroInt {
+				node.Retracted = true
+				node.RetractRationale = r.Rationale
+				break
+			}
+		}
+	}
+
+	return nil
+}
+
+// getLatestRetracts returns the retract directives in the go.mod file of the latest version of the module
+func getLatestRetracts(rootPath string, proxy *ProxyClient, path string) ([]*modfile.Retract, error) {
+	versions, err := getLocalVersions(rootPath, path)
+	if err != nil {
+		return nil, err
+	}
+	if proxy != nil {
+		proxyVersions, err := proxy.List(path)
+		if err != nil {
+			log.Warnf("list versions from the proxies failed, will use the module cache only. module: %s, error: %s", path, err.Error())
+		}
+		versions = append(versions, proxyVersions...)
+		semver.Sort(versions)
+	}
+	latest := getLatestVersion(versions)
+	if latest == constant.EmptyString {
+		return nil, nil
+	}
+
+	f, err := readLatestModFile(rootPath, proxy, path, latest)
+	if err != nil {
+		log.Warnf("parse go.mod file of the latest version failed, will ignore the retractions. module: %s, error: %s", path+AtString+latest, err.Error())
+		return nil, nil
+	}
+	if f == nil {
+		return nil, nil
+	}
+
+	return f.Retract, nil
+}
+
+// readLatestModFile reads the go.mod file of the latest version
+func readLatestModFile(rootPath string, proxy *ProxyClient, path, version string) (*modfile.File, error) {
+	f, err := readCachedModFile(rootPath, path, version)
+	if err != nil || f != nil || proxy == nil {
+		return f, err
+	}
+
+	data, err := proxy.GoMod(path, version)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	f, err = modfile.ParseLax(path+AtString+version+modFileExt, data, nil)
+
+	return f, errors.Trace(err)
+}
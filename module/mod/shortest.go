@@ -0,0 +1,142 @@
+package mod
+
+import (
+	"container/heap"
+
+	"github.com/romberli/go-util/constant"
+)
+
+// chainItem is a prefix of a parent chain which starts at a top node
+type chainItem struct {
+	chain    []*Node
+	estimate int
+}
+
+// chainHeap is the priority queue of the chain prefixes
+type chainHeap []*chainItem
+
+func (h chainHeap) Len() int { return len(h) }
+
+func (h chainHeap) Less(i, j int) bool {
+	if h[i].estimate != h[j].estimate {
+		return h[i].estimate < h[j].estimate
+	}
+
+	return lessChain(h[i].chain, h[j].chain)
+}
+
+func (h chainHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *chainHeap) Push(x any) { *h = append(*h, x.(*chainItem)) }
+
+func (h *chainHeap) Pop() any {
+	old := *h
+	item := old[len(old)-constant.OneInt]
+	*h = old[:len(old)-constant.OneInt]
+
+	return item
+}
+
+// GetShortestParentChains returns at most k shortest parent chains from the top nodes to the target nodes
+func GetShortestParentChains(targets []*Node, k, maxDepth int) [][]*Node {
+	distances, tops := getTargetDistances(targets)
+
+	h := &chainHeap{}
+	for _, top := range tops {
+		heap.Push(h, &chainItem{chain: []*Node{top}, estimate: distances[top]})
+	}
+
+	var result [][]*Node
+	for h.Len() > constant.ZeroInt && len(result) < k {
+		item := heap.Pop(h).(*chainItem)
+		if maxDepth > constant.ZeroInt && item.estimate > maxDepth {
+			// all the other chains are longer
+			break
+		}
+		last := item.chain[len(item.chain)-constant.OneInt]
+		if distances[last] == constant.ZeroInt {
+			// the prefix reaches a target node
+			result = append(result, item.chain)
+			continue
+		}
+		if last.Pruned {
+			continue
+		}
+		for _, childNode := range last.ChildNodes {
+			distance, ok := distances[childNode]
+			if !ok || inChain(item.chain, childNode) {
+				continue
+			}
+			chain := make([]*Node, len(item.chain), len(item.chain)+constant.OneInt)
+			copy(chain, item.chain)
+			heap.Push(h, &chainItem{chain: append(chain, childNode), estimate: len(chain) + distance})
+		}
+	}
+
+	return result
+}
+
+// getTargetDistances returns the distance from each ancestor to the nearest target node and the top nodes
+func getTargetDistances(targets []*Node) (map[*Node]int, []*Node) {
+	distances := make(map[*Node]int)
+	var (
+		queue []*Node
+		tops  []*Node
+	)
+	for _, target := range targets {
+		if _, ok := distances[target]; !ok {
+			distances[target] = constant.ZeroInt
+			queue = append(queue, target)
+		}
+	}
+
+	for len(queue) > constant.ZeroInt {
+		node := queue[constant.ZeroInt]
+		queue = queue[constant.OneInt:]
+		if len(node.ParentNodes) == constant.ZeroInt {
+			tops = append(tops, node)
+		}
+		for _, parentNode := range node.ParentNodes {
+			if parentNode.Pruned {
+				continue
+			}
+			if _, ok := distances[parentNode]; !ok {
+				distances[parentNode] = distances[node] + constant.OneInt
+				queue = append(queue, parentNode)
+			}
+		}
+	}
+
+	return distances, tops
+}
+
+// lessChain returns if the chain a is sorted before the chain b by the lexical path
+func lessChain(a, b []*Node) bool {
+	for i := constant.ZeroInt; i < len(a) && i < len(b); i++ {
+		if a[i] == b[i] {
+			continue
+		}
+		if lessNode(a[i], b[i]) {
+			return true
+		}
+		if lessNode(b[i], a[i]) {
+			return false
+		}
+		if a[i].RootPath != b[i].RootPath {
+			return a[i].RootPath < b[i].RootPath
+		}
+	}
+
+	return len(a) < len(b)
+}
+
+// inChain returns if the node is in the chain
+func inChain(chain []*Node, node *Node) bool {
+	for _, n := range chain {
+		if n == node {
+			return true
+		}
+	}
+
+	return false
+}
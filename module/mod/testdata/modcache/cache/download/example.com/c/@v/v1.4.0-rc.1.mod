@@ -0,0 +1,5 @@
+module example.com/c
+
+go 1.21
+
+retract [v1.0.0, v1.3.0]
@@ -0,0 +1,5 @@
+module example.com/e
+
+go 1.21
+
+require example.com/d v1.0.0
@@ -0,0 +1,5 @@
+module example.com/f
+
+go 1.21
+
+require example.com/g v1.0.0
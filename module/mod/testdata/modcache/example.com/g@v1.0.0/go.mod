@@ -0,0 +1,5 @@
+module example.com/g
+
+go 1.21
+
+require example.com/h v1.0.0
@@ -0,0 +1,5 @@
+module example.com/i
+
+go 1.16
+
+require example.com/j v1.0.0
@@ -0,0 +1,5 @@
+module example.com/j
+
+go 1.21
+
+require example.com/k v1.0.0
@@ -0,0 +1,9 @@
+module example.com/nested/tools
+
+go 1.21
+
+require (
+	example.com/a v1.0.0
+	example.com/c v1.2.0
+	example.com/e v1.0.0
+)
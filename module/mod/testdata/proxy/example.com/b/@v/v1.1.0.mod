@@ -0,0 +1,8 @@
+module example.com/b
+
+go 1.21
+
+require (
+	example.com/c v1.2.0
+	example.com/d v1.0.0
+)
@@ -0,0 +1,5 @@
+module example.com/g
+
+go 1.21
+
+retract v1.0.0 // contains a data race
@@ -0,0 +1,7 @@
+module example.com/prune
+
+go 1.21
+
+require example.com/f v1.0.0
+
+require example.com/i v1.0.0 // indirect
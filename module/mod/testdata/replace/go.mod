@@ -0,0 +1,12 @@
+module example.com/replace
+
+go 1.21
+
+require (
+	example.com/a v1.0.0
+	example.com/b v1.1.0
+)
+
+replace example.com/a => ./a
+
+replace example.com/b v1.1.0 => example.com/e v1.0.0
@@ -0,0 +1,10 @@
+module example.com/root
+
+go 1.21
+
+require (
+	example.com/a v1.0.0
+	example.com/b v1.1.0
+)
+
+require example.com/d v1.0.0 // indirect
@@ -0,0 +1,13 @@
+module example.com/why
+
+go 1.21
+
+require (
+	example.com/wa v1.0.0
+	example.com/wb v1.0.0
+)
+
+replace (
+	example.com/wa v1.0.0 => ./wa
+	example.com/wb v1.0.0 => ./wb
+)
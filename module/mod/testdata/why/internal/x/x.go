@@ -0,0 +1,7 @@
+package x
+
+import "example.com/wa/pa"
+
+func X() string {
+	return pa.A()
+}
@@ -0,0 +1,11 @@
+package main
+
+import (
+	"fmt"
+
+	"example.com/why/internal/x"
+)
+
+func main() {
+	fmt.Println(x.X())
+}
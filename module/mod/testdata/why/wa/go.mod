@@ -0,0 +1,5 @@
+module example.com/wa
+
+go 1.21
+
+require example.com/wb v1.0.0
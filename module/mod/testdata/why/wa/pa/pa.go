@@ -0,0 +1,7 @@
+package pa
+
+import "example.com/wb/pb"
+
+func A() string {
+	return "a" + pb.B()
+}
@@ -0,0 +1,5 @@
+package pb
+
+func B() string {
+	return "b"
+}
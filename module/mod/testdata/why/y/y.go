@@ -0,0 +1,7 @@
+package y
+
+import "example.com/wb/pb"
+
+func Y() string {
+	return pb.B()
+}
@@ -0,0 +1,8 @@
+module example.com/x
+
+go 1.21
+
+require (
+	example.com/a v1.0.0
+	example.com/y v0.1.0
+)
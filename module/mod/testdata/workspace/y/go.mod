@@ -0,0 +1,5 @@
+module example.com/y
+
+go 1.21
+
+require example.com/b v1.1.0
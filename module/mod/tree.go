@@ -0,0 +1,117 @@
+package mod
+
+import (
+	"github.com/romberli/go-util/constant"
+)
+
+const (
+	outputIndent     = "│ "
+	outputIndentLast = "  "
+	repeatedString   = "(*)"
+)
+
+// GetChildTree returns the lines of the dependency tree of the node, the repeated subtrees are suffixed with (*)
+func (n *Node) GetChildTree(maxDepth int) []string {
+	return n.GetChildTreeWithFilter(maxDepth, nil)
+}
+
+// GetChildTreeWithFilter returns the lines of the dependency tree of the node which lead to the matched nodes
+func (n *Node) GetChildTreeWithFilter(maxDepth int, filter func(node *Node) bool) []string {
+	output := n.String()
+	if n.FullName == constant.EmptyString {
+		output = n.RootPath
+	}
+	lines := []string{output}
+
+	var relevant map[*Node]bool
+	if filter != nil {
+		relevant = n.getRelevantNodes(filter)
+	}
+	n.getChildTree(&lines, constant.EmptyString, constant.OneInt, maxDepth, relevant, map[*Node]bool{n: true})
+
+	return lines
+}
+
+// getRelevantNodes returns the descendants of the node which match the filter and their ancestors
+func (n *Node) getRelevantNodes(filter func(node *Node) bool) map[*Node]bool {
+	relevant := make(map[*Node]bool)
+	var queue []*Node
+
+	visited := map[*Node]bool{n: true}
+	descendants := []*Node{n}
+	for i := constant.ZeroInt; i < len(descendants); i++ {
+		node := descendants[i]
+		if filter(node) {
+			relevant[node] = true
+			queue = append(queue, node)
+		}
+		if node.Pruned {
+			continue
+		}
+		for _, childNode := range node.ChildNodes {
+			if !visited[childNode] {
+				visited[childNode] = true
+				descendants = append(descendants, childNode)
+			}
+		}
+	}
+
+	for len(queue) > constant.ZeroInt {
+		node := queue[constant.ZeroInt]
+		queue = queue[constant.OneInt:]
+		for _, parentNode := range node.ParentNodes {
+			if parentNode.Pruned || !visited[parentNode] || relevant[parentNode] {
+				continue
+			}
+			relevant[parentNode] = true
+			queue = append(queue, parentNode)
+		}
+	}
+
+	return relevant
+}
+
+// getChildTree appends the lines of the subtree of the node
+func (n *Node) getChildTree(lines *[]string, indent string, depth, maxDepth int, relevant, expanded map[*Node]bool) {
+	childNodes := n.getTreeChildNodes(relevant)
+	for i, childNode := range childNodes {
+		prefix, childIndent := outputPrefix, indent+outputIndent
+		if i == len(childNodes)-constant.OneInt {
+			prefix, childIndent = outputPrefixLast, indent+outputIndentLast
+		}
+		output := indent + prefix + getChildString(n, childNode)
+		if expanded[childNode] {
+			if len(childNode.getTreeChildNodes(relevant)) > constant.ZeroInt {
+				output += constant.SpaceString + repeatedString
+			}
+			*lines = append(*lines, output)
+			continue
+		}
+		*lines = append(*lines, output)
+		if maxDepth > constant.ZeroInt && depth >= maxDepth {
+			// the subtree is not expanded, so it could be expanded when the node appears at a lower depth
+			continue
+		}
+		expanded[childNode] = true
+		childNode.getChildTree(lines, childIndent, depth+constant.OneInt, maxDepth, relevant, expanded)
+	}
+}
+
+// getTreeChildNodes returns the child nodes which are printed in the tree
+func (n *Node) getTreeChildNodes(relevant map[*Node]bool) []*Node {
+	if n.Pruned {
+		return nil
+	}
+	if relevant == nil {
+		return n.ChildNodes
+	}
+
+	var result []*Node
+	for _, childNode := range n.ChildNodes {
+		if relevant[childNode] {
+			result = append(result, childNode)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,225 @@
+package mod
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/go-util/constant"
+	"github.com/romberli/log"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+const (
+	vendorDirName         = "vendor"
+	vendorModulesFileName = "modules.txt"
+
+	vendorModuleLinePrefix     = "# "
+	vendorAnnotationLinePrefix = "## "
+	vendorExplicitAnnotation   = "explicit"
+	vendorGoAnnotationPrefix   = "go "
+	vendorArrowString          = "=>"
+)
+
+// vendorModule is a module line of vendor/modules.txt
+type vendorModule struct {
+	module.Version
+	explicit  bool
+	goVersion string
+}
+
+// VendorResolver reads vendor/modules.txt to get the requirements
+type VendorResolver struct {
+	vendorDir    string
+	indirect     bool
+	modules      []*vendorModule
+	versions     map[module.Version]*vendorModule
+	replacements map[module.Version]module.Version
+}
+
+// NewVendorResolver returns a new *VendorResolver
+func NewVendorResolver(vendorDir string, indirect bool) (*VendorResolver, error) {
+	vr := &VendorResolver{
+		vendorDir:    vendorDir,
+		indirect:     indirect,
+		versions:     make(map[module.Version]*vendorModule),
+		replacements: make(map[module.Version]module.Version),
+	}
+
+	data, err := os.ReadFile(filepath.Join(vendorDir, vendorModulesFileName))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var current *vendorModule
+	for _, line := range strings.Split(string(data), constant.CRLFString) {
+		switch {
+		case strings.HasPrefix(line, vendorAnnotationLinePrefix):
+			if current == nil {
+				continue
+			}
+			for _, annotation := range strings.Split(strings.TrimPrefix(line, vendorAnnotationLinePrefix), constant.SemicolonString) {
+				annotation = strings.TrimSpace(annotation)
+				if annotation == vendorExplicitAnnotation {
+					current.explicit = true
+				}
+				if strings.HasPrefix(annotation, vendorGoAnnotationPrefix) {
+					current.goVersion = strings.TrimSpace(strings.TrimPrefix(annotation, vendorGoAnnotationPrefix))
+				}
+			}
+		case strings.HasPrefix(line, vendorModuleLinePrefix):
+			current = nil
+			fields := strings.Fields(strings.TrimPrefix(line, vendorModuleLinePrefix))
+			if len(fields) < constant.TwoInt {
+				return nil, errors.Errorf("NewVendorResolver(): module line format is not valid. line: %s", line)
+			}
+			old := module.Version{Path: fields[constant.ZeroInt]}
+			rest := fields[constant.OneInt:]
+			if rest[constant.ZeroInt] != vendorArrowString {
+				old.Version = rest[constant.ZeroInt]
+				rest = rest[constant.OneInt:]
+			}
+			if len(rest) > constant.ZeroInt {
+				// replacement, it is formatted as "=> path [version]"
+				if rest[constant.ZeroInt] != vendorArrowString || len(rest) < constant.TwoInt || len(rest) > constant.ThreeInt {
+					return nil, errors.Errorf("NewVendorResolver(): module line format is not valid. line: %s", line)
+				}
+				replacement := module.Version{Path: rest[constant.OneInt]}
+				if len(rest) == constant.ThreeInt {
+					replacement.Version = rest[constant.TwoInt]
+				}
+				vr.replacements[old] = replacement
+			}
+			if old.Version != constant.EmptyString {
+				current = &vendorModule{Version: old}
+				vr.modules = append(vr.modules, current)
+				vr.versions[old] = current
+			}
+		}
+	}
+
+	return vr, nil
+}
+
+// GetChildPackages returns the direct requirements of the node
+func (vr *VendorResolver) GetChildPackages(node *Node) ([]string, error) {
+	if node.IsRoot() {
+		if node.RootPath != filepath.Dir(vr.vendorDir) {
+			return NewModFileResolver(nil, vr.indirect).GetChildPackages(node)
+		}
+		return vr.getMainPackages(node)
+	}
+
+	vm, ok := vr.versions[module.Version{Path: node.Name, Version: node.Version}]
+	if ok {
+		node.GoVersion = vm.goVersion
+	}
+
+	f, err := parseModFile(filepath.Join(vr.vendorDir, filepath.FromSlash(node.Name), goModFileName), false)
+	if err != nil {
+		return nil, err
+	}
+	if f == nil {
+		log.Debugf("vendored go.mod file does not exist, will treat it as having no requirements. module: %s", node.FullName)
+		return nil, nil
+	}
+	setGoVersion(node, f)
+
+	return getRequirePackages(f, vr.indirect), nil
+}
+
+// getMainPackages returns the modules in vendor/modules.txt as the requirements of the main module
+func (vr *VendorResolver) getMainPackages(node *Node) ([]string, error) {
+	f, err := parseModFile(filepath.Join(node.RootPath, goModFileName), true)
+	if err != nil {
+		return nil, err
+	}
+	if f != nil {
+		setGoVersion(node, f)
+	}
+
+	var packages []string
+	for _, vm := range vr.modules {
+		if !vm.explicit && !vr.indirect {
+			continue
+		}
+		pkg := vm.Path + AtString + vm.Version.Version
+		if !vm.explicit {
+			pkg += indirectSuffix
+		}
+		packages = append(packages, pkg)
+	}
+
+	return packages, nil
+}
+
+// CheckConsistency returns the inconsistencies between vendor/modules.txt and the go.mod file
+func (vr *VendorResolver) CheckConsistency(f *modfile.File) []string {
+	if f == nil {
+		return nil
+	}
+
+	var result []string
+
+	explicitModules := make(map[string]*vendorModule)
+	for _, vm := range vr.modules {
+		if vm.explicit {
+			explicitModules[vm.Path] = vm
+		}
+	}
+
+	required := make(map[string]bool)
+	for _, require := range f.Require {
+		required[require.Mod.Path] = true
+		vm, ok := explicitModules[require.Mod.Path]
+		if !ok {
+			result = append(result, fmt.Sprintf("%s is explicitly required in go.mod, but not marked as explicit in vendor/modules.txt",
+				require.Mod.String()))
+			continue
+		}
+		if vm.Version.Version != require.Mod.Version {
+			result = append(result, fmt.Sprintf("%s is explicitly required in go.mod, but vendor/modules.txt indicates %s",
+				require.Mod.String(), vm.Version.String()))
+		}
+	}
+	for _, vm := range vr.modules {
+		if vm.explicit && !required[vm.Path] {
+			result = append(result, fmt.Sprintf("%s is marked as explicit in vendor/modules.txt, but not explicitly required in go.mod",
+				vm.Version.String()))
+		}
+	}
+
+	replaced := make(map[module.Version]bool)
+	for _, replace := range f.Replace {
+		replaced[replace.Old] = true
+		replacement, ok := vr.replacements[replace.Old]
+		if !ok {
+			result = append(result, fmt.Sprintf("%s is replaced in go.mod, but not marked as replaced in vendor/modules.txt",
+				replace.Old.String()))
+			continue
+		}
+		if replacement != replace.New {
+			result = append(result, fmt.Sprintf("%s is replaced by %s in go.mod, but marked as replaced by %s in vendor/modules.txt",
+				replace.Old.String(), replace.New.String(), replacement.String()))
+		}
+	}
+	for _, vm := range vr.modules {
+		_, ok := vr.replacements[vm.Version]
+		if ok && !replaced[vm.Version] && !replaced[module.Version{Path: vm.Path}] {
+			result = append(result, fmt.Sprintf("%s is marked as replaced in vendor/modules.txt, but not replaced in go.mod",
+				vm.Version.String()))
+		}
+	}
+
+	return result
+}
+
+// hasVendorModulesFile returns if vendor/modules.txt exists in given directory
+func hasVendorModulesFile(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, vendorDirName, vendorModulesFileName))
+
+	return err == nil
+}
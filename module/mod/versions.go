@@ -0,0 +1,225 @@
+package mod
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/go-util/constant"
+	"golang.org/x/mod/semver"
+
+	"github.com/romberli/go-mod/config"
+)
+
+const (
+	semverLevelNone       = "none"
+	semverLevelMajor      = "major"
+	semverLevelMinor      = "minor"
+	semverLevelPatch      = "patch"
+	semverLevelPrerelease = "prerelease"
+
+	selectedString = "selected"
+	behindString   = "behind"
+	aheadString    = "ahead"
+
+	noVersionsMessage      = "module %s is not required by any module"
+	versionsHeaderTemplate = "%s (selected: %s)\n"
+
+	jsonIndent = "  "
+)
+
+// SemverDistance is the distance from a version to another one at the most significant differing level
+type SemverDistance struct {
+	Level string `json:"level"`
+	Delta int    `json:"delta"`
+}
+
+// NewSemverDistance returns the distance from the version to the other version
+func NewSemverDistance(version, other string) *SemverDistance {
+	v := parseSemver(version)
+	o := parseSemver(other)
+	levels := []string{semverLevelMajor, semverLevelMinor, semverLevelPatch}
+	for i, level := range levels {
+		if v[i] != o[i] {
+			return &SemverDistance{Level: level, Delta: v[i] - o[i]}
+		}
+	}
+	if c := semver.Compare(version, other); c != constant.ZeroInt {
+		return &SemverDistance{Level: semverLevelPrerelease, Delta: c}
+	}
+
+	return &SemverDistance{Level: semverLevelNone}
+}
+
+// parseSemver returns the major, minor and patch numbers of the version, the missing numbers are zero
+func parseSemver(version string) [3]int {
+	var result [3]int
+
+	canonical := strings.TrimPrefix(semver.Canonical(version), "v")
+	canonical = strings.SplitN(canonical, "-", constant.TwoInt)[constant.ZeroInt]
+	for i, s := range strings.SplitN(canonical, constant.DotString, len(result)) {
+		result[i], _ = strconv.Atoi(s)
+	}
+
+	return result
+}
+
+// String returns the text representation of the distance, such as "2 minor behind"
+func (sd *SemverDistance) String() string {
+	if sd.Level == semverLevelNone {
+		return constant.EmptyString
+	}
+
+	direction := behindString
+	delta := sd.Delta
+	if delta > constant.ZeroInt {
+		direction = aheadString
+	} else {
+		delta = -delta
+	}
+	if sd.Level == semverLevelPrerelease {
+		return sd.Level + constant.SpaceString + direction
+	}
+
+	return strconv.Itoa(delta) + constant.SpaceString + sd.Level + constant.SpaceString + direction
+}
+
+// Requirer is a module which requires a version of the module directly
+type Requirer struct {
+	Module   string `json:"module"`
+	Indirect bool   `json:"indirect"`
+}
+
+// ModuleVersion is a version of the module in the graph
+type ModuleVersion struct {
+	Version   string          `json:"version"`
+	Selected  bool            `json:"selected"`
+	Retracted bool            `json:"retracted,omitempty"`
+	Replace   string          `json:"replace,omitempty"`
+	Distance  *SemverDistance `json:"distance,omitempty"`
+	Requirers []*Requirer     `json:"requirers"`
+}
+
+// ModuleVersions contains all the versions of the module path in the graph
+type ModuleVersions struct {
+	Module   string           `json:"module"`
+	Selected string           `json:"selected"`
+	Versions []*ModuleVersion `json:"versions"`
+}
+
+// GetModuleVersions returns all the versions of given module path with their direct requirers
+func (c *Controller) GetModuleVersions(name string) *ModuleVersions {
+	mv := &ModuleVersions{Module: name, Versions: []*ModuleVersion{}}
+
+	var nodes []*Node
+	for _, node := range c.GetNodes(name, constant.EmptyString) {
+		// the name is matched exactly, as the versions of different module paths are not comparable
+		if node.IsRoot() || node.Name != name {
+			continue
+		}
+		nodes = append(nodes, node)
+		if node.Selected {
+			mv.Selected = node.Version
+		}
+	}
+
+	for _, node := range nodes {
+		version := &ModuleVersion{
+			Version:   node.Version,
+			Selected:  node.Selected,
+			Retracted: node.Retracted,
+			Requirers: []*Requirer{},
+		}
+		if node.IsReplaced() {
+			version.Replace = strings.TrimSpace(node.ReplaceName + constant.SpaceString + node.ReplaceVersion)
+		}
+		if mv.Selected != constant.EmptyString {
+			version.Distance = NewSemverDistance(node.Version, mv.Selected)
+		}
+
+		var parentNodes []*Node
+		for _, parentNode := range node.ParentNodes {
+			if !parentNode.Pruned {
+				parentNodes = append(parentNodes, parentNode)
+			}
+		}
+		sortNodes(parentNodes)
+		for _, parentNode := range parentNodes {
+			module := parentNode.FullName
+			if module == constant.EmptyString {
+				module = parentNode.RootPath
+			}
+			version.Requirers = append(version.Requirers, &Requirer{Module: module, Indirect: parentNode.IsIndirectChild(node)})
+		}
+
+		mv.Versions = append(mv.Versions, version)
+	}
+
+	return mv
+}
+
+// PrintVersions prints all the versions of given module path with their direct requirers
+func (c *Controller) PrintVersions(name string) error {
+	err := c.Init()
+	if err != nil {
+		return err
+	}
+
+	mv := c.GetModuleVersions(name)
+	if c.outputFormat == config.ModOutputFormatJSON {
+		data, err := json.MarshalIndent(mv, constant.EmptyString, jsonIndent)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(mv.Versions) == constant.ZeroInt {
+		fmt.Println(fmt.Sprintf(noVersionsMessage, name))
+		return nil
+	}
+
+	fmt.Printf(versionsHeaderTemplate, mv.Module, mv.Selected)
+	for i, version := range mv.Versions {
+		fmt.Println(getOutputPrefix(i, len(mv.Versions)) + version.String())
+		indent := outputIndent
+		if i == len(mv.Versions)-constant.OneInt {
+			indent = outputIndentLast
+		}
+		for j, requirer := range version.Requirers {
+			output := requirer.Module
+			if requirer.Indirect {
+				output += constant.SpaceString + constant.LeftParenthesisString + indirectString + constant.RightParenthesisString
+			}
+			fmt.Println(indent + getOutputPrefix(j, len(version.Requirers)) + output)
+		}
+	}
+
+	return nil
+}
+
+// String returns the text representation of the module version
+func (mv *ModuleVersion) String() string {
+	output := mv.Version
+	if mv.Replace != constant.EmptyString {
+		output += replaceArrowString + mv.Replace
+	}
+	var notes []string
+	if mv.Selected {
+		notes = append(notes, selectedString)
+	}
+	if mv.Retracted {
+		notes = append(notes, retractedString)
+	}
+	if mv.Distance != nil && mv.Distance.Level != semverLevelNone {
+		notes = append(notes, mv.Distance.String())
+	}
+	if len(notes) > constant.ZeroInt {
+		output += constant.SpaceString + constant.LeftParenthesisString + strings.Join(notes, constant.CommaString+constant.SpaceString) + constant.RightParenthesisString
+	}
+
+	return output
+}
@@ -0,0 +1,243 @@
+package mod
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/romberli/go-util/constant"
+	"github.com/romberli/log"
+)
+
+const (
+	allPackagesPattern        = "./..."
+	goListDepsCommandTemplate = "go list -e -deps -json=ImportPath,Dir,GoFiles,Imports,Module,Error %s"
+
+	importTemplate       = "%s imports %s"
+	noImportChainMessage = "main modules do not import any package of module %s"
+)
+
+// listPackage is a package in the output of go list -json
+type listPackage struct {
+	ImportPath string
+	Dir        string
+	GoFiles    []string
+	Imports    []string
+	Module     *listModule
+	Error      *listError
+}
+
+// listModule is the module of a package in the output of go list -json
+type listModule struct {
+	Path    string
+	Version string
+	Main    bool
+}
+
+// listError is the error of a package in the output of go list -json
+type listError struct {
+	Err string
+}
+
+// ImportStep is an import statement in the import chain
+type ImportStep struct {
+	Package  string
+	Position string
+	Module   string
+	Node     *Node
+}
+
+// String returns the text representation of the import step
+func (is *ImportStep) String() string {
+	module := is.Module
+	if is.Node != nil {
+		module = is.Node.String()
+	}
+
+	return fmt.Sprintf(importTemplate, is.Position, is.Package) +
+		constant.SpaceString + constant.LeftParenthesisString + module + constant.RightParenthesisString
+}
+
+// ImportChain is the shortest import path from a package of the main modules
+type ImportChain struct {
+	Package string
+	Steps   []*ImportStep
+}
+
+// GetImportChain returns the shortest import path from the main modules to a package of given module
+func (c *Controller) GetImportChain(name, version string) (*ImportChain, error) {
+	pkgs, err := c.loadPackages()
+	if err != nil {
+		return nil, err
+	}
+
+	importers := make(map[*listPackage]*listPackage)
+	visited := make(map[*listPackage]bool)
+	var queue []*listPackage
+	for _, pkg := range pkgs {
+		if pkg.Module != nil && pkg.Module.Main && !visited[pkg] {
+			visited[pkg] = true
+			queue = append(queue, pkg)
+		}
+	}
+	sortPackages(queue)
+
+	for len(queue) > constant.ZeroInt {
+		pkg := queue[constant.ZeroInt]
+		queue = queue[constant.OneInt:]
+		if pkg.Module != nil && !pkg.Module.Main && pkg.Module.Path == name && (version == constant.EmptyString || pkg.Module.Version == version) {
+			return c.getImportChain(pkg, importers)
+		}
+
+		var imports []*listPackage
+		for _, importPath := range pkg.Imports {
+			importedPkg, ok := pkgs[importPath]
+			if ok && !visited[importedPkg] && importedPkg.Module != nil {
+				imports = append(imports, importedPkg)
+			}
+		}
+		sortPackages(imports)
+		for _, importedPkg := range imports {
+			visited[importedPkg] = true
+			importers[importedPkg] = pkg
+			queue = append(queue, importedPkg)
+		}
+	}
+
+	return nil, nil
+}
+
+// getImportChain returns the import chain which ends at given package with the importers of the packages
+func (c *Controller) getImportChain(pkg *listPackage, importers map[*listPackage]*listPackage) (*ImportChain, error) {
+	var steps []*ImportStep
+	for {
+		importer, ok := importers[pkg]
+		if !ok {
+			break
+		}
+		position, err := c.getImportPosition(importer, pkg.ImportPath)
+		if err != nil {
+			return nil, err
+		}
+		module := pkg.Module.Path + AtString + pkg.Module.Version
+		steps = append([]*ImportStep{{Package: pkg.ImportPath, Position: position, Module: module, Node: c.m[module]}}, steps...)
+		pkg = importer
+	}
+
+	return &ImportChain{Package: pkg.ImportPath, Steps: steps}, nil
+}
+
+// getImportPosition returns the file:line of the import statement of given import path in the package
+func (c *Controller) getImportPosition(pkg *listPackage, importPath string) (string, error) {
+	fset := token.NewFileSet()
+	for _, file := range pkg.GoFiles {
+		f, err := parser.ParseFile(fset, filepath.Join(pkg.Dir, file), nil, parser.ImportsOnly)
+		if err != nil {
+			return constant.EmptyString, errors.Trace(err)
+		}
+		for _, spec := range f.Imports {
+			path, err := strconv.Unquote(spec.Path.Value)
+			if err != nil || path != importPath {
+				continue
+			}
+			position := fset.Position(spec.Pos())
+			filename := position.Filename
+			rel, err := filepath.Rel(c.baseDir, filename)
+			if err == nil && !strings.HasPrefix(rel, constant.DotString+constant.DotString) {
+				filename = rel
+			}
+
+			return filename + constant.ColonString + strconv.Itoa(position.Line), nil
+		}
+	}
+
+	return pkg.ImportPath, nil
+}
+
+// loadPackages loads the packages of the main modules and all their dependencies with go list
+func (c *Controller) loadPackages() (map[string]*listPackage, error) {
+	patterns := []string{allPackagesPattern}
+	if c.RootNode.FullName != constant.EmptyString {
+		patterns = nil
+		for _, rootNode := range c.RootNodes {
+			patterns = append(patterns, filepath.Join(rootNode.RootPath, allPackagesPattern))
+		}
+	}
+
+	command := fmt.Sprintf(goListDepsCommandTemplate, strings.Join(patterns, constant.SpaceString))
+	output, err := c.executor.ExecuteCommand(c.baseDir, command, false)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return parsePackages(output)
+}
+
+// parsePackages parses the json output of go list
+func parsePackages(output string) (map[string]*listPackage, error) {
+	pkgs := make(map[string]*listPackage)
+	index := strings.Index(output, constant.LeftBraceString)
+	if index < constant.ZeroInt {
+		return pkgs, nil
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(output[index:]))
+	for decoder.More() {
+		pkg := &listPackage{}
+		err := decoder.Decode(pkg)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if pkg.Error != nil {
+			log.Warnf("package has errors, the imports of it may be incomplete. package: %s, error: %s", pkg.ImportPath, pkg.Error.Err)
+		}
+		pkgs[pkg.ImportPath] = pkg
+	}
+
+	return pkgs, nil
+}
+
+// PrintImportChain prints the shortest import path from the main modules to a package of given module
+func (c *Controller) PrintImportChain(name, version string, modUseCompileVersion bool) error {
+	err := c.Init()
+	if err != nil {
+		return err
+	}
+
+	v := version
+	if modUseCompileVersion {
+		v, err = c.GetCompileVersion(name)
+		if err != nil {
+			return err
+		}
+	}
+
+	chain, err := c.GetImportChain(name, v)
+	if err != nil {
+		return err
+	}
+	if chain == nil {
+		fmt.Println(fmt.Sprintf(noImportChainMessage, name))
+		return nil
+	}
+
+	fmt.Println(chain.Package)
+	for i, step := range chain.Steps {
+		fmt.Printf("%s%s\n", strings.Repeat(constant.SpaceString, defaultSpaceNum*(i+constant.OneInt)), outputPrefixLast+step.String())
+	}
+
+	return nil
+}
+
+// sortPackages sorts the packages by the package path
+func sortPackages(pkgs []*listPackage) {
+	sort.Slice(pkgs, func(i, j int) bool {
+		return pkgs[i].ImportPath < pkgs[j].ImportPath
+	})
+}
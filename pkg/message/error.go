@@ -37,6 +37,14 @@ const (
 	ErrGetPidFromPidFile          = 400030
 	ErrSetSid                     = 400031
 	ErrRemovePidFile              = 400032
+	ErrNotValidModResolver        = 400033
+	ErrNotValidModWorkers         = 400034
+	ErrNotValidModMaxChains       = 400035
+	ErrNotValidModMaxDepth        = 400036
+	ErrNotValidModParentFormat    = 400037
+	ErrNotValidModKShortest       = 400038
+	ErrNotValidModOutputFormat    = 400039
+	ErrNotValidModNameRegex       = 400040
 )
 
 func initErrorMessage() {
@@ -72,4 +80,12 @@ func initErrorMessage() {
 	Messages[ErrGetPidFromPidFile] = config.NewErrMessage(DefaultMessageHeader, ErrGetPidFromPidFile, "get pid from pid file failed. pid file: %s")
 	Messages[ErrSetSid] = config.NewErrMessage(DefaultMessageHeader, ErrSetSid, "set sid failed when daemonizing server")
 	Messages[ErrRemovePidFile] = config.NewErrMessage(DefaultMessageHeader, ErrRemovePidFile, "remove pid file failed. pid file: %s")
+	Messages[ErrNotValidModResolver] = config.NewErrMessage(DefaultMessageHeader, ErrNotValidModResolver, "mod resolver must be one of [auto, modfile, list, graph, vendor], %s is not valid")
+	Messages[ErrNotValidModWorkers] = config.NewErrMessage(DefaultMessageHeader, ErrNotValidModWorkers, "mod workers must be larger than 0, %d is not valid")
+	Messages[ErrNotValidModMaxChains] = config.NewErrMessage(DefaultMessageHeader, ErrNotValidModMaxChains, "mod max chains must not be smaller than 0, %d is not valid")
+	Messages[ErrNotValidModMaxDepth] = config.NewErrMessage(DefaultMessageHeader, ErrNotValidModMaxDepth, "mod max depth must not be smaller than 0, %d is not valid")
+	Messages[ErrNotValidModParentFormat] = config.NewErrMessage(DefaultMessageHeader, ErrNotValidModParentFormat, "mod parent format must be one of [chain, count, dag], %s is not valid")
+	Messages[ErrNotValidModKShortest] = config.NewErrMessage(DefaultMessageHeader, ErrNotValidModKShortest, "mod k shortest must not be smaller than 0, %d is not valid")
+	Messages[ErrNotValidModOutputFormat] = config.NewErrMessage(DefaultMessageHeader, ErrNotValidModOutputFormat, "mod output format must be either text or json, %s is not valid")
+	Messages[ErrNotValidModNameRegex] = config.NewErrMessage(DefaultMessageHeader, ErrNotValidModNameRegex, "mod name regex must be a valid regular expression, %s is not valid. error: %s")
 }
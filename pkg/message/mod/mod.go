@@ -18,9 +18,18 @@ const (
 
 	// info
 	InfoModParentPrintParentChain = 200001
+	InfoModCacheClean             = 200002
 
 	// error
-	ErrModParentPrintParentChain = 400001
+	ErrModParentPrintParentChain  = 400001
+	ErrModCacheClean              = 400002
+	ErrModCyclesPrintCycles       = 400003
+	ErrModChildPrintChildTree     = 400004
+	ErrModAllPrintAll             = 400005
+	ErrModWhyPrintImportChain     = 400006
+	ErrModVersionsPrintVersions   = 400007
+	ErrModConflictsPrintConflicts = 400008
+	ErrModMajorsPrintMajors       = 400009
 )
 
 func initModDebugMessage() {
@@ -28,10 +37,27 @@ func initModDebugMessage() {
 }
 
 func initModInfoMessage() {
-
+	message.Messages[InfoModCacheClean] = config.NewErrMessage(message.DefaultMessageHeader, InfoModCacheClean,
+		"mod: clean graph cache completed. cache directory: %s")
 }
 
 func initModErrorMessage() {
 	message.Messages[ErrModParentPrintParentChain] = config.NewErrMessage(message.DefaultMessageHeader, ErrModParentPrintParentChain,
 		"mod: print parent chain failed. mod directory: %s, mod name: %s, mod version: %s")
+	message.Messages[ErrModCacheClean] = config.NewErrMessage(message.DefaultMessageHeader, ErrModCacheClean,
+		"mod: clean graph cache failed. cache directory: %s")
+	message.Messages[ErrModCyclesPrintCycles] = config.NewErrMessage(message.DefaultMessageHeader, ErrModCyclesPrintCycles,
+		"mod: print cycles failed. mod directory: %s")
+	message.Messages[ErrModChildPrintChildTree] = config.NewErrMessage(message.DefaultMessageHeader, ErrModChildPrintChildTree,
+		"mod: print child tree failed. mod directory: %s, mod name: %s, mod version: %s")
+	message.Messages[ErrModAllPrintAll] = config.NewErrMessage(message.DefaultMessageHeader, ErrModAllPrintAll,
+		"mod: print all failed. mod directory: %s")
+	message.Messages[ErrModWhyPrintImportChain] = config.NewErrMessage(message.DefaultMessageHeader, ErrModWhyPrintImportChain,
+		"mod: print import chain failed. mod directory: %s, mod name: %s, mod version: %s")
+	message.Messages[ErrModVersionsPrintVersions] = config.NewErrMessage(message.DefaultMessageHeader, ErrModVersionsPrintVersions,
+		"mod: print versions failed. mod directory: %s, mod name: %s")
+	message.Messages[ErrModConflictsPrintConflicts] = config.NewErrMessage(message.DefaultMessageHeader, ErrModConflictsPrintConflicts,
+		"mod: print conflicts failed. mod directory: %s")
+	message.Messages[ErrModMajorsPrintMajors] = config.NewErrMessage(message.DefaultMessageHeader, ErrModMajorsPrintMajors,
+		"mod: print multiple major versions failed. mod directory: %s")
 }